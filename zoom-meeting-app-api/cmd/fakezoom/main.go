@@ -0,0 +1,24 @@
+// Command fakezoom serves the in-memory fake Zoom API from package zoomtest,
+// so the app can be developed without network access or a Zoom account.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"zoom-meeting-app/zoom/zoomtest"
+)
+
+func main() {
+	addr := flag.String("addr", ":9000", "address to listen on")
+	clientID := flag.String("client-id", zoomtest.DefaultClientID, "OAuth client ID accepted by the fake")
+	clientSecret := flag.String("client-secret", zoomtest.DefaultClientSecret, "OAuth client secret accepted by the fake")
+	flag.Parse()
+
+	fake := zoomtest.NewFake()
+	fake.ClientID = *clientID
+	fake.ClientSecret = *clientSecret
+
+	log.Printf("Fake Zoom API listening on %s (OAuth at /oauth, API at /v2)", *addr)
+	log.Fatal(http.ListenAndServe(*addr, fake))
+}
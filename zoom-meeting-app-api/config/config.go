@@ -0,0 +1,429 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/mail"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds the settings loaded once at startup
+type Config struct {
+	// ListenAddr is the address the HTTP server listens on, e.g. ":8000"
+	ListenAddr string `yaml:"listen_addr"`
+	// APIBaseURL is the public URL of this API, used to build OAuth redirect URIs
+	APIBaseURL string `yaml:"api_base_url"`
+	// FrontendURL is the origin allowed by CORS
+	FrontendURL string `yaml:"frontend_url"`
+	// RedirectFrontend is where users land after connecting Zoom
+	RedirectFrontend string `yaml:"redirect_frontend"`
+	// SessionSecret signs the session cookie
+	SessionSecret string `yaml:"session_secret"`
+	// CookieDomain is the domain of the token cookies, empty for the API's
+	// host only
+	CookieDomain string `yaml:"cookie_domain"`
+	// CookieSecure limits the token cookies to HTTPS, as needed in production
+	CookieSecure bool `yaml:"cookie_secure"`
+	// MeetingSyncInterval is how often meetings are reconciled with Zoom in
+	// the background, 0 disables the sync
+	MeetingSyncInterval time.Duration `yaml:"meeting_sync_interval"`
+	// RequireVerifiedEmail makes Login refuse accounts whose email address
+	// has not been verified
+	RequireVerifiedEmail bool `yaml:"require_verified_email"`
+	// BootstrapAdminEmail names a user who is made admin at startup while no
+	// admin exists, e.g. after upgrading from before roles
+	BootstrapAdminEmail string `yaml:"bootstrap_admin_email"`
+
+	Database DatabaseConfig `yaml:"database"`
+	Zoom     ZoomConfig     `yaml:"zoom"`
+	JWT      JWTConfig      `yaml:"jwt"`
+	Mail     MailConfig     `yaml:"mail"`
+}
+
+// DatabaseConfig holds the Postgres connection settings
+type DatabaseConfig struct {
+	Host     string `yaml:"host"`
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	Name     string `yaml:"name"`
+	Port     string `yaml:"port"`
+	SSLMode  string `yaml:"sslmode"`
+}
+
+// DSN returns the Postgres connection string
+func (d DatabaseConfig) DSN() string {
+	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s",
+		d.Host, d.User, d.Password, d.Name, d.Port, d.SSLMode)
+}
+
+// ZoomConfig holds the Zoom OAuth app credentials and the endpoints the app talks to
+type ZoomConfig struct {
+	ClientID     string `yaml:"client_id"`
+	ClientSecret string `yaml:"client_secret"`
+	OAuthHost    string `yaml:"oauth_host"`
+	APIHost      string `yaml:"api_host"`
+	CallbackPath string `yaml:"callback_path"`
+	// WebhookSecret is the secret token of the app's event subscription,
+	// webhooks are rejected when it is empty
+	WebhookSecret string `yaml:"webhook_secret"`
+}
+
+// APIBaseURL returns the base URL of the Zoom REST API v2
+func (z ZoomConfig) APIBaseURL() string {
+	return z.APIHost + "/v2"
+}
+
+// MailConfig holds the SMTP server outgoing emails are sent through
+type MailConfig struct {
+	// SMTPHost is the SMTP server, emails are only logged when it is empty
+	SMTPHost string `yaml:"smtp_host"`
+	SMTPPort string `yaml:"smtp_port"`
+	// Username and Password authenticate with the server when set
+	Username string `yaml:"smtp_username"`
+	Password string `yaml:"smtp_password"`
+	// From is the sender address, required with SMTPHost
+	From string `yaml:"from"`
+}
+
+// JWTConfig holds the settings of the app's own access and refresh tokens
+type JWTConfig struct {
+	// Secret is an HS256 key with the ID "default"
+	Secret string `yaml:"secret"`
+	// Keys are additional signing keys. Tokens are signed with ActiveKey;
+	// the other keys only verify the tokens they signed before a rotation.
+	Keys []JWTKeyConfig `yaml:"keys"`
+	// ActiveKey is the ID of the key new tokens are signed with, by default
+	// "default" when Secret is set, else the first key
+	ActiveKey string `yaml:"active_key"`
+	// Issuer is the iss claim of issued tokens, by default APIBaseURL
+	Issuer string `yaml:"issuer"`
+	// Audience is the aud claim tokens are issued for and must carry
+	Audience string `yaml:"audience"`
+	// Leeway is the clock skew allowed when checking token times
+	Leeway time.Duration `yaml:"leeway"`
+	// AccessTokenTTL is how long an access token is valid
+	AccessTokenTTL time.Duration `yaml:"access_token_ttl"`
+	// RefreshTokenTTL is how long a refresh token can be exchanged for a
+	// new access token
+	RefreshTokenTTL time.Duration `yaml:"refresh_token_ttl"`
+}
+
+// DefaultJWTKeyID is the ID of the key given by JWTConfig.Secret
+const DefaultJWTKeyID = "default"
+
+// JWTKeyConfig is a key that signs or verifies access tokens
+type JWTKeyConfig struct {
+	// ID is sent as the kid header of the tokens signed with the key
+	ID string `yaml:"id"`
+	// Algorithm is one of HS256, HS384, HS512, RS256, RS384, RS512 or EdDSA
+	Algorithm string `yaml:"algorithm"`
+	// Secret is the key of HMAC algorithms
+	Secret string `yaml:"secret"`
+	// KeyFile is a PEM file with the RSA or Ed25519 key. A public key
+	// only verifies tokens, as needed for keys that have been rotated out.
+	KeyFile string `yaml:"key_file"`
+}
+
+// RedirectURI returns the OAuth redirect URI registered in the Zoom app
+func (c *Config) RedirectURI() string {
+	return c.APIBaseURL + c.Zoom.CallbackPath
+}
+
+// Load reads the configuration once at startup. Values come from, in order of
+// increasing precedence: built-in defaults, the YAML file named by CONFIG_FILE,
+// and environment variables (a .env file is loaded into the environment when
+// present, without overriding variables that are already set).
+func Load() (*Config, error) {
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to load .env file: %w", err)
+	}
+
+	cfg := defaults()
+
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := cfg.loadEnv(); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func defaults() *Config {
+	return &Config{
+		ListenAddr:          ":8000",
+		MeetingSyncInterval: 5 * time.Minute,
+		APIBaseURL:          "http://localhost:8000",
+		FrontendURL:         "http://localhost:3000",
+		RedirectFrontend:    "http://localhost:3000",
+		Database: DatabaseConfig{
+			Port:    "5432",
+			SSLMode: "disable",
+		},
+		Zoom: ZoomConfig{
+			OAuthHost:    "https://zoom.us",
+			APIHost:      "https://api.zoom.us",
+			CallbackPath: "/auth/callback",
+		},
+		Mail: MailConfig{
+			SMTPPort: "587",
+		},
+		JWT: JWTConfig{
+			Audience:        "zoom-meeting-app",
+			Leeway:          30 * time.Second,
+			AccessTokenTTL:  15 * time.Minute,
+			RefreshTokenTTL: 30 * 24 * time.Hour,
+		},
+	}
+}
+
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	if err := yaml.Unmarshal(data, c); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
+func (c *Config) loadEnv() error {
+	for key, target := range map[string]*string{
+		"LISTEN_ADDR":           &c.ListenAddr,
+		"API_BASE_URL":          &c.APIBaseURL,
+		"URL_FRONTEND":          &c.FrontendURL,
+		"REDIRECT_FRONTEND":     &c.RedirectFrontend,
+		"SESSION_SECRET":        &c.SessionSecret,
+		"COOKIE_DOMAIN":         &c.CookieDomain,
+		"BOOTSTRAP_ADMIN_EMAIL": &c.BootstrapAdminEmail,
+		"DB_HOST":               &c.Database.Host,
+		"DB_USER":               &c.Database.User,
+		"DB_PASSWORD":           &c.Database.Password,
+		"DB_NAME":               &c.Database.Name,
+		"DB_PORT":               &c.Database.Port,
+		"DB_SSLMODE":            &c.Database.SSLMode,
+		"ZOOM_CLIENT_ID":        &c.Zoom.ClientID,
+		"ZOOM_CLIENT_SECRET":    &c.Zoom.ClientSecret,
+		"ZOOM_OAUTH_HOST":       &c.Zoom.OAuthHost,
+		"ZOOM_API_HOST":         &c.Zoom.APIHost,
+		"ZOOM_CALLBACK_PATH":    &c.Zoom.CallbackPath,
+		"ZOOM_WEBHOOK_SECRET":   &c.Zoom.WebhookSecret,
+		"JWT_SECRET":            &c.JWT.Secret,
+		"JWT_ACTIVE_KEY":        &c.JWT.ActiveKey,
+		"JWT_ISSUER":            &c.JWT.Issuer,
+		"JWT_AUDIENCE":          &c.JWT.Audience,
+		"SMTP_HOST":             &c.Mail.SMTPHost,
+		"SMTP_PORT":             &c.Mail.SMTPPort,
+		"SMTP_USERNAME":         &c.Mail.Username,
+		"SMTP_PASSWORD":         &c.Mail.Password,
+		"MAIL_FROM":             &c.Mail.From,
+	} {
+		if value := os.Getenv(key); value != "" {
+			*target = value
+		}
+	}
+
+	// JWT_KEYS lists keys as comma separated id:algorithm:key_file entries
+	if value := os.Getenv("JWT_KEYS"); value != "" {
+		c.JWT.Keys = nil
+		for _, entry := range strings.Split(value, ",") {
+			parts := strings.SplitN(strings.TrimSpace(entry), ":", 3)
+			if len(parts) != 3 {
+				return fmt.Errorf("invalid configuration: JWT_KEYS entry %q is not id:algorithm:key_file", entry)
+			}
+			c.JWT.Keys = append(c.JWT.Keys, JWTKeyConfig{ID: parts[0], Algorithm: parts[1], KeyFile: parts[2]})
+		}
+	}
+
+	for key, target := range map[string]*bool{
+		"REQUIRE_VERIFIED_EMAIL": &c.RequireVerifiedEmail,
+		"COOKIE_SECURE":          &c.CookieSecure,
+	} {
+		if value := os.Getenv(key); value != "" {
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("invalid configuration: %s is invalid: %w", key, err)
+			}
+			*target = enabled
+		}
+	}
+
+	for key, target := range map[string]*time.Duration{
+		"MEETING_SYNC_INTERVAL": &c.MeetingSyncInterval,
+		"JWT_ACCESS_TOKEN_TTL":  &c.JWT.AccessTokenTTL,
+		"JWT_REFRESH_TOKEN_TTL": &c.JWT.RefreshTokenTTL,
+		"JWT_LEEWAY":            &c.JWT.Leeway,
+	} {
+		if value := os.Getenv(key); value != "" {
+			duration, err := time.ParseDuration(value)
+			if err != nil {
+				return fmt.Errorf("invalid configuration: %s is invalid: %w", key, err)
+			}
+			*target = duration
+		}
+	}
+	return nil
+}
+
+// validate checks the required values and reports every problem at once
+func (c *Config) validate() error {
+	var problems []string
+
+	for name, value := range map[string]string{
+		"LISTEN_ADDR":        c.ListenAddr,
+		"SESSION_SECRET":     c.SessionSecret,
+		"DB_HOST":            c.Database.Host,
+		"DB_USER":            c.Database.User,
+		"DB_NAME":            c.Database.Name,
+		"DB_PORT":            c.Database.Port,
+		"ZOOM_CLIENT_ID":     c.Zoom.ClientID,
+		"ZOOM_CLIENT_SECRET": c.Zoom.ClientSecret,
+	} {
+		if value == "" {
+			problems = append(problems, name+" is required")
+		}
+	}
+
+	for name, value := range map[string]*string{
+		"API_BASE_URL":      &c.APIBaseURL,
+		"URL_FRONTEND":      &c.FrontendURL,
+		"REDIRECT_FRONTEND": &c.RedirectFrontend,
+		"ZOOM_OAUTH_HOST":   &c.Zoom.OAuthHost,
+		"ZOOM_API_HOST":     &c.Zoom.APIHost,
+	} {
+		*value = strings.TrimRight(*value, "/")
+		if err := validateURL(*value); err != nil {
+			problems = append(problems, fmt.Sprintf("%s is invalid: %v", name, err))
+		}
+	}
+
+	if placeholderSecrets[c.SessionSecret] {
+		problems = append(problems, "SESSION_SECRET must not be a placeholder value")
+	}
+	if c.MeetingSyncInterval < 0 {
+		problems = append(problems, "MEETING_SYNC_INTERVAL must not be negative")
+	}
+	problems = append(problems, c.JWT.validate()...)
+	if c.JWT.Issuer == "" {
+		c.JWT.Issuer = c.APIBaseURL
+	}
+	if c.JWT.Audience == "" {
+		problems = append(problems, "JWT_AUDIENCE is required")
+	}
+	if c.JWT.Leeway < 0 {
+		problems = append(problems, "JWT_LEEWAY must not be negative")
+	}
+	if c.JWT.AccessTokenTTL <= 0 {
+		problems = append(problems, "JWT_ACCESS_TOKEN_TTL must be positive")
+	}
+	if c.JWT.RefreshTokenTTL <= c.JWT.AccessTokenTTL {
+		problems = append(problems, "JWT_REFRESH_TOKEN_TTL must be longer than JWT_ACCESS_TOKEN_TTL")
+	}
+
+	if c.BootstrapAdminEmail != "" {
+		if _, err := mail.ParseAddress(c.BootstrapAdminEmail); err != nil {
+			problems = append(problems, fmt.Sprintf("BOOTSTRAP_ADMIN_EMAIL is invalid: %v", err))
+		}
+	}
+
+	if c.Mail.SMTPHost != "" {
+		if c.Mail.SMTPPort == "" {
+			problems = append(problems, "SMTP_PORT is required with SMTP_HOST")
+		}
+		if c.Mail.From == "" {
+			problems = append(problems, "MAIL_FROM is required with SMTP_HOST")
+		} else if _, err := mail.ParseAddress(c.Mail.From); err != nil {
+			problems = append(problems, fmt.Sprintf("MAIL_FROM is invalid: %v", err))
+		}
+	}
+
+	if !strings.HasPrefix(c.Zoom.CallbackPath, "/") {
+		problems = append(problems, "ZOOM_CALLBACK_PATH must start with /")
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("invalid configuration: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// placeholderSecrets are example secrets from the docs and sample files.
+// Anyone can sign tokens or sessions with them, so they are refused.
+var placeholderSecrets = map[string]bool{
+	"change-me":        true,
+	"your_secret_key":  true,
+	"super-secret-key": true,
+}
+
+// validate checks the signing keys and fills in the default active key
+func (j *JWTConfig) validate() []string {
+	var problems []string
+	if j.Secret == "" && len(j.Keys) == 0 {
+		return []string{"JWT_SECRET or JWT_KEYS is required"}
+	}
+
+	if placeholderSecrets[j.Secret] {
+		problems = append(problems, "JWT_SECRET must not be a placeholder value")
+	}
+
+	ids := make(map[string]bool)
+	if j.Secret != "" {
+		ids[DefaultJWTKeyID] = true
+	}
+	for _, key := range j.Keys {
+		switch {
+		case key.ID == "":
+			problems = append(problems, "JWT key IDs must not be empty")
+		case ids[key.ID]:
+			problems = append(problems, fmt.Sprintf("JWT key ID %q is used twice", key.ID))
+		}
+		ids[key.ID] = true
+		if (key.Secret == "") == (key.KeyFile == "") {
+			problems = append(problems, fmt.Sprintf("JWT key %q needs either a secret or a key file", key.ID))
+		}
+		if placeholderSecrets[key.Secret] {
+			problems = append(problems, fmt.Sprintf("JWT key %q must not use a placeholder secret", key.ID))
+		}
+	}
+
+	if j.ActiveKey == "" {
+		if j.Secret != "" {
+			j.ActiveKey = DefaultJWTKeyID
+		} else {
+			j.ActiveKey = j.Keys[0].ID
+		}
+	}
+	if !ids[j.ActiveKey] {
+		problems = append(problems, fmt.Sprintf("JWT_ACTIVE_KEY %q is not a configured key", j.ActiveKey))
+	}
+	return problems
+}
+
+func validateURL(value string) error {
+	u, err := url.Parse(value)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("%q is not an absolute http(s) URL", value)
+	}
+	return nil
+}
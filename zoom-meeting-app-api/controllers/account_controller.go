@@ -0,0 +1,283 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+	"zoom-meeting-app/database"
+	"zoom-meeting-app/mail"
+	"zoom-meeting-app/models"
+	"zoom-meeting-app/utils"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// How long emailed account tokens can be used
+const (
+	passwordResetTTL     = time.Hour
+	emailVerificationTTL = 48 * time.Hour
+)
+
+// emailTimeout bounds sending one email in the background
+const emailTimeout = 30 * time.Second
+
+// errInvalidAccountToken is returned for unknown, used or expired account tokens
+var errInvalidAccountToken = errors.New("invalid account token")
+
+// pendingEmails tracks the emails being sent in the background
+var pendingEmails sync.WaitGroup
+
+// Request a password reset (POST /auth/forgot-password)
+//
+// Emails a link to reset the password when an active account uses the given
+// address. The response is the same either way and does not wait for the
+// email, so it does not reveal which addresses have accounts.
+func ForgotPassword(c *gin.Context) {
+	var input struct {
+		Email string `json:"email"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	email := models.NormalizeEmail(input.Email)
+	if email == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "email is required"})
+		return
+	}
+
+	// Look the account up in the background too, so the response time does
+	// not tell whether it exists
+	sendInBackground("password reset", func(ctx context.Context) error {
+		var user models.User
+		if err := database.DB.WithContext(ctx).Where("email = ?", email).First(&user).Error; err != nil || !user.Active() {
+			return nil
+		}
+		if err := sendPasswordResetEmail(ctx, user); err != nil {
+			return fmt.Errorf("user %d: %w", user.ID, err)
+		}
+		return nil
+	})
+
+	c.JSON(http.StatusOK, gin.H{"message": "If an account uses this email, a password reset link has been sent"})
+}
+
+// Reset a password (POST /auth/reset-password)
+//
+// Sets a new password with a token from a password reset email and signs out
+// every session of the user. As the token proves the user reads the
+// account's email, the address counts as verified too.
+func ResetPassword(c *gin.Context) {
+	var input struct {
+		Token    string `json:"token" binding:"required"`
+		Password string `json:"password" binding:"required,min=8,password"`
+	}
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reset password"})
+		return
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		user, err := useAccountToken(tx, input.Token, models.AccountTokenPasswordReset)
+		if err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{"password": string(hashedPassword)}
+		if !user.EmailVerified() {
+			updates["email_verified_at"] = time.Now()
+		}
+		if err := tx.Model(&user).Updates(updates).Error; err != nil {
+			return err
+		}
+		return revokeUserSessions(tx, user.ID)
+	})
+
+	if errors.Is(err, errInvalidAccountToken) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired token"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reset password"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password has been reset"})
+}
+
+// Verify an email address (GET /auth/verify-email?token=)
+//
+// Opened from the link in the verification email sent on registration.
+func VerifyEmail(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "token is required"})
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		user, err := useAccountToken(tx, token, models.AccountTokenEmailVerification)
+		if err != nil {
+			return err
+		}
+		if user.EmailVerified() {
+			return nil
+		}
+		return tx.Model(&user).Update("email_verified_at", time.Now()).Error
+	})
+
+	if errors.Is(err, errInvalidAccountToken) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired token"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify email"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Email verified"})
+}
+
+// sendInBackground runs send, which sends an email, without holding up the
+// request, giving it emailTimeout and logging its failure
+func sendInBackground(what string, send func(ctx context.Context) error) {
+	pendingEmails.Add(1)
+	go func() {
+		defer pendingEmails.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), emailTimeout)
+		defer cancel()
+		if err := send(ctx); err != nil {
+			log.Printf("%s email: %v", what, err)
+		}
+	}()
+}
+
+// WaitForEmails waits until the emails being sent in the background are
+// sent or have failed
+func WaitForEmails() {
+	pendingEmails.Wait()
+}
+
+// sendPasswordResetEmail emails user a link to the frontend's password reset
+// page
+func sendPasswordResetEmail(ctx context.Context, user models.User) error {
+	token, err := issueAccountToken(database.DB.WithContext(ctx), user.ID, models.AccountTokenPasswordReset, passwordResetTTL)
+	if err != nil {
+		return err
+	}
+
+	link := appConfig.FrontendURL + "/reset-password?token=" + token
+	return utils.Mailer().Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nOpen the link below within %s to choose a new password:\n\n%s\n\n"+
+			"If you did not ask to reset your password, you can ignore this email.\n", user.Name, formatHours(passwordResetTTL), link),
+	})
+}
+
+// sendVerificationEmail emails user a link that verifies their address
+func sendVerificationEmail(ctx context.Context, user models.User) error {
+	token, err := issueAccountToken(database.DB.WithContext(ctx), user.ID, models.AccountTokenEmailVerification, emailVerificationTTL)
+	if err != nil {
+		return err
+	}
+
+	link := appConfig.APIBaseURL + "/auth/verify-email?token=" + token
+	return utils.Mailer().Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Hi %s,\n\nOpen the link below within %s to verify your email address:\n\n%s\n",
+			user.Name, formatHours(emailVerificationTTL), link),
+	})
+}
+
+// formatHours writes a whole number of hours for the emails
+func formatHours(d time.Duration) string {
+	if hours := int(d.Hours()); hours != 1 {
+		return fmt.Sprintf("%d hours", hours)
+	}
+	return "1 hour"
+}
+
+// issueAccountToken stores a new token for userID, replacing the user's
+// unused tokens of the same purpose, and returns it
+func issueAccountToken(tx *gorm.DB, userID uint, purpose models.AccountTokenPurpose, ttl time.Duration) (string, error) {
+	token, err := utils.NewRandomToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	err = tx.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).Delete(&models.AccountToken{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.AccountToken{
+			UserID:    userID,
+			Purpose:   purpose,
+			TokenHash: utils.HashToken(token),
+			ExpiresAt: time.Now().Add(ttl),
+		}).Error
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// useAccountToken claims an unused, unexpired token of the given purpose and
+// returns its user, who must be active
+func useAccountToken(tx *gorm.DB, token string, purpose models.AccountTokenPurpose) (models.User, error) {
+	var stored models.AccountToken
+	if err := tx.Where("token_hash = ? AND purpose = ?", utils.HashToken(token), purpose).First(&stored).Error; err != nil {
+		return models.User{}, errInvalidAccountToken
+	}
+	if stored.UsedAt != nil || time.Now().After(stored.ExpiresAt) {
+		return models.User{}, errInvalidAccountToken
+	}
+
+	// Claim the token, unless a concurrent request already did
+	result := tx.Model(&models.AccountToken{}).
+		Where("id = ? AND used_at IS NULL", stored.ID).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return models.User{}, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return models.User{}, errInvalidAccountToken
+	}
+
+	var user models.User
+	if err := tx.First(&user, stored.UserID).Error; err != nil || !user.Active() {
+		return models.User{}, errInvalidAccountToken
+	}
+	return user, nil
+}
+
+// revokeUserSessions revokes every refresh token family of a user, with the
+// access tokens issued with them
+func revokeUserSessions(tx *gorm.DB, userID uint) error {
+	var families []string
+	if err := tx.Model(&models.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Distinct().Pluck("family_id", &families).Error; err != nil {
+		return err
+	}
+	for _, family := range families {
+		if err := revokeTokenFamily(tx, family); err != nil {
+			return err
+		}
+	}
+	return nil
+}
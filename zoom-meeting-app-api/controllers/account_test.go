@@ -0,0 +1,371 @@
+package controllers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+	"zoom-meeting-app/config"
+	"zoom-meeting-app/controllers"
+	"zoom-meeting-app/database"
+	"zoom-meeting-app/mail"
+	"zoom-meeting-app/middleware"
+	"zoom-meeting-app/models"
+	"zoom-meeting-app/utils"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// accountAPI serves the unauthenticated /auth routes, sending emails to an
+// in-memory mailbox
+type accountAPI struct {
+	t      *testing.T
+	router *gin.Engine
+	mail   *mail.MemorySender
+	cfg    *config.Config // Read by the controllers on every request
+}
+
+func newAccountAPI(t *testing.T, requireVerifiedEmail bool) *accountAPI {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	openTestDatabase(t)
+
+	cfg := &config.Config{
+		APIBaseURL:           "http://api.example.com",
+		FrontendURL:          "http://app.example.com",
+		RequireVerifiedEmail: requireVerifiedEmail,
+	}
+	controllers.Init(cfg, nil)
+	err := utils.InitJWT(config.JWTConfig{
+		Secret:          "secret",
+		ActiveKey:       config.DefaultJWTKeyID,
+		Issuer:          "http://api.example.com",
+		Audience:        "zoom-meeting-app",
+		AccessTokenTTL:  15 * time.Minute,
+		RefreshTokenTTL: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("InitJWT: %v", err)
+	}
+	mailbox := &mail.MemorySender{}
+	utils.SetMailer(mailbox)
+	t.Cleanup(controllers.WaitForEmails)
+
+	router := gin.New()
+	router.POST("/auth/register", controllers.Register)
+	router.POST("/auth/login", controllers.Login)
+	router.POST("/auth/refresh", controllers.RefreshToken)
+	router.POST("/auth/logout", middleware.AuthMiddleware(), controllers.Logout)
+	router.GET("/auth/me", middleware.AuthMiddleware(), controllers.Me)
+	router.POST("/auth/forgot-password", controllers.ForgotPassword)
+	router.POST("/auth/reset-password", controllers.ResetPassword)
+	router.GET("/auth/verify-email", controllers.VerifyEmail)
+
+	return &accountAPI{t: t, router: router, mail: mailbox, cfg: cfg}
+}
+
+func (api *accountAPI) do(method, path string, body interface{}) *httptest.ResponseRecorder {
+	api.t.Helper()
+	return api.doAs("", method, path, body)
+}
+
+// doAs sends a request with accessToken as its bearer token, if not empty
+func (api *accountAPI) doAs(accessToken, method, path string, body interface{}) *httptest.ResponseRecorder {
+	api.t.Helper()
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			api.t.Fatal(err)
+		}
+	}
+	req := httptest.NewRequest(method, path, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	if accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+	w := httptest.NewRecorder()
+	api.router.ServeHTTP(w, req)
+	return w
+}
+
+// createUser stores a user with the given password, verified or not
+func (api *accountAPI) createUser(email, password string, verified bool) models.User {
+	api.t.Helper()
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		api.t.Fatal(err)
+	}
+	user := models.User{Name: "Test", Email: email, Password: string(hashed)}
+	if verified {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+	}
+	if err := database.DB.Create(&user).Error; err != nil {
+		api.t.Fatalf("create user: %v", err)
+	}
+	return user
+}
+
+var tokenLink = regexp.MustCompile(`\?token=([A-Za-z0-9_-]+)`)
+
+// mailedToken returns the token in the link of the last email sent to address
+func (api *accountAPI) mailedToken(to string) string {
+	api.t.Helper()
+	controllers.WaitForEmails()
+	msg, ok := api.mail.Last(to)
+	if !ok {
+		api.t.Fatalf("no email sent to %s", to)
+	}
+	match := tokenLink.FindStringSubmatch(msg.Body)
+	if match == nil {
+		api.t.Fatalf("email without token link: %s", msg.Body)
+	}
+	return match[1]
+}
+
+func (api *accountAPI) login(email, password string) *httptest.ResponseRecorder {
+	api.t.Helper()
+	return api.do(http.MethodPost, "/auth/login", gin.H{"email": email, "password": password})
+}
+
+func TestPasswordReset(t *testing.T) {
+	api := newAccountAPI(t, false)
+	api.createUser("alice@example.com", "old-password", true)
+
+	w := api.login("alice@example.com", "old-password")
+	var session struct {
+		Data struct {
+			RefreshToken string `json:"refreshToken"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &session); err != nil || session.Data.RefreshToken == "" {
+		t.Fatalf("login: %d %s", w.Code, w.Body)
+	}
+
+	// Unknown addresses get the same answer and no email
+	if w := api.do(http.MethodPost, "/auth/forgot-password", gin.H{"email": "nobody@example.com"}); w.Code != http.StatusOK {
+		t.Fatalf("forgot unknown address: %d %s", w.Code, w.Body)
+	}
+	controllers.WaitForEmails()
+	if n := len(api.mail.Messages()); n != 0 {
+		t.Fatalf("%d emails sent for an unknown address", n)
+	}
+
+	if w := api.do(http.MethodPost, "/auth/forgot-password", gin.H{"email": "alice@example.com"}); w.Code != http.StatusOK {
+		t.Fatalf("forgot password: %d %s", w.Code, w.Body)
+	}
+	token := api.mailedToken("alice@example.com")
+
+	if w := api.do(http.MethodPost, "/auth/reset-password", gin.H{"token": token, "password": "short"}); w.Code != http.StatusBadRequest {
+		t.Errorf("short password: %d %s", w.Code, w.Body)
+	}
+	if w := api.do(http.MethodPost, "/auth/reset-password", gin.H{"token": token, "password": "new-passw0rd"}); w.Code != http.StatusOK {
+		t.Fatalf("reset password: %d %s", w.Code, w.Body)
+	}
+
+	if w := api.login("alice@example.com", "old-password"); w.Code != http.StatusUnauthorized {
+		t.Errorf("login with old password: %d", w.Code)
+	}
+	if w := api.login("alice@example.com", "new-passw0rd"); w.Code != http.StatusOK {
+		t.Errorf("login with new password: %d %s", w.Code, w.Body)
+	}
+
+	// The token works once, and sessions from before the reset are gone
+	if w := api.do(http.MethodPost, "/auth/reset-password", gin.H{"token": token, "password": "other-passw0rd"}); w.Code != http.StatusBadRequest {
+		t.Errorf("reused token: %d %s", w.Code, w.Body)
+	}
+	if w := api.do(http.MethodPost, "/auth/refresh", gin.H{"refreshToken": session.Data.RefreshToken}); w.Code != http.StatusUnauthorized {
+		t.Errorf("refresh with a session from before the reset: %d %s", w.Code, w.Body)
+	}
+}
+
+func TestPasswordResetTokenExpiresAndIsReplaced(t *testing.T) {
+	api := newAccountAPI(t, false)
+	user := api.createUser("bob@example.com", "old-password", true)
+
+	api.do(http.MethodPost, "/auth/forgot-password", gin.H{"email": user.Email})
+	first := api.mailedToken(user.Email)
+	api.do(http.MethodPost, "/auth/forgot-password", gin.H{"email": user.Email})
+	second := api.mailedToken(user.Email)
+
+	if w := api.do(http.MethodPost, "/auth/reset-password", gin.H{"token": first, "password": "new-passw0rd"}); w.Code != http.StatusBadRequest {
+		t.Errorf("replaced token: %d %s", w.Code, w.Body)
+	}
+
+	database.DB.Model(&models.AccountToken{}).Where("user_id = ?", user.ID).Update("expires_at", time.Now().Add(-time.Minute))
+	if w := api.do(http.MethodPost, "/auth/reset-password", gin.H{"token": second, "password": "new-passw0rd"}); w.Code != http.StatusBadRequest {
+		t.Errorf("expired token: %d %s", w.Code, w.Body)
+	}
+
+	var stored models.AccountToken
+	database.DB.Where("user_id = ?", user.ID).First(&stored)
+	if stored.TokenHash == second || stored.TokenHash != utils.HashToken(second) {
+		t.Errorf("token stored as %q, want its hash", stored.TokenHash)
+	}
+}
+
+// blockingSender holds every email until release is closed, reporting
+// whether the email came with a deadline
+type blockingSender struct {
+	release  chan struct{}
+	deadline chan bool
+}
+
+func (s *blockingSender) Send(ctx context.Context, msg mail.Message) error {
+	_, ok := ctx.Deadline()
+	s.deadline <- ok
+	<-s.release
+	return nil
+}
+
+func TestAccountEmailsDoNotHoldUpRequests(t *testing.T) {
+	api := newAccountAPI(t, false)
+	api.createUser("dana@example.com", "passw0rd", true)
+	sender := &blockingSender{release: make(chan struct{}), deadline: make(chan bool, 2)}
+	utils.SetMailer(sender)
+	defer close(sender.release)
+
+	if w := api.do(http.MethodPost, "/auth/forgot-password", gin.H{"email": "dana@example.com"}); w.Code != http.StatusOK {
+		t.Fatalf("forgot password: %d %s", w.Code, w.Body)
+	}
+	if w := api.do(http.MethodPost, "/auth/register", gin.H{"name": "Erin", "email": "erin@example.com", "password": "passw0rd"}); w.Code != http.StatusOK {
+		t.Fatalf("register: %d %s", w.Code, w.Body)
+	}
+
+	for i := 0; i < 2; i++ {
+		if !<-sender.deadline {
+			t.Error("email sent without a deadline")
+		}
+	}
+}
+
+func TestLoginRequiresVerifiedEmail(t *testing.T) {
+	api := newAccountAPI(t, true)
+
+	w := api.do(http.MethodPost, "/auth/register", gin.H{"name": "Carol", "email": "carol@example.com", "password": "carol-passw0rd"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("register: %d %s", w.Code, w.Body)
+	}
+	token := api.mailedToken("carol@example.com")
+
+	if w := api.login("carol@example.com", "carol-passw0rd"); w.Code != http.StatusForbidden {
+		t.Fatalf("login before verification: %d %s", w.Code, w.Body)
+	}
+
+	if w := api.do(http.MethodGet, "/auth/verify-email?token="+token, nil); w.Code != http.StatusOK {
+		t.Fatalf("verify email: %d %s", w.Code, w.Body)
+	}
+	if w := api.do(http.MethodGet, "/auth/verify-email?token="+token, nil); w.Code != http.StatusBadRequest {
+		t.Errorf("reused verification token: %d %s", w.Code, w.Body)
+	}
+
+	if w := api.login("carol@example.com", "carol-passw0rd"); w.Code != http.StatusOK {
+		t.Errorf("login after verification: %d %s", w.Code, w.Body)
+	}
+}
+
+func TestRegisterValidation(t *testing.T) {
+	api := newAccountAPI(t, false)
+	api.createUser("taken@example.com", "passw0rd", true)
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		fields map[string]string // Failing fields and their messages
+	}{
+		{"valid", `{"name":"Dave","email":"dave@example.com","password":"passw0rd"}`, http.StatusOK, nil},
+		{"duplicate email", `{"name":"Eve","email":"taken@example.com","password":"passw0rd"}`, http.StatusConflict, nil},
+		{"malformed JSON", `{"name":`, http.StatusBadRequest, nil},
+		{"empty body", `{}`, http.StatusBadRequest, map[string]string{
+			"name":     "is required",
+			"email":    "is required",
+			"password": "is required",
+		}},
+		{"invalid fields", `{"name":"E","email":"not-an-email","password":"short1"}`, http.StatusBadRequest, map[string]string{
+			"name":     "must be at least 2 characters",
+			"email":    "must be a valid email address",
+			"password": "must be at least 8 characters",
+		}},
+		{"password without digit", `{"name":"Frank","email":"frank@example.com","password":"password"}`, http.StatusBadRequest, map[string]string{
+			"password": "must contain a letter and a digit and be at most 72 bytes",
+		}},
+		{"password longer than bcrypt hashes", `{"name":"Grace","email":"grace@example.com","password":"` + strings.Repeat("a1", 37) + `"}`, http.StatusBadRequest, map[string]string{
+			"password": "must contain a letter and a digit and be at most 72 bytes",
+		}},
+		{"name too long", `{"name":"` + strings.Repeat("x", 101) + `","email":"heidi@example.com","password":"passw0rd"}`, http.StatusBadRequest, map[string]string{
+			"name": "must be at most 100 characters",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/auth/register", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			api.router.ServeHTTP(w, req)
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.status, w.Body)
+			}
+			if tt.fields == nil {
+				return
+			}
+
+			var response struct {
+				Fields []struct {
+					Field   string `json:"field"`
+					Message string `json:"message"`
+				} `json:"fields"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatal(err)
+			}
+			got := map[string]string{}
+			for _, f := range response.Fields {
+				got[f.Field] = f.Message
+			}
+			if !reflect.DeepEqual(got, tt.fields) {
+				t.Errorf("fields = %v, want %v", got, tt.fields)
+			}
+		})
+	}
+
+	var count int64
+	database.DB.Model(&models.User{}).Where("email = ?", "taken@example.com").Count(&count)
+	if count != 1 {
+		t.Errorf("%d users with the taken email, want 1", count)
+	}
+}
+
+func TestEmailsIgnoreCase(t *testing.T) {
+	api := newAccountAPI(t, false)
+
+	w := api.do(http.MethodPost, "/auth/register", gin.H{"name": "Dave", "email": "  Dave@Example.COM ", "password": "passw0rd"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("register: %d %s", w.Code, w.Body)
+	}
+	var user models.User
+	if err := database.DB.First(&user).Error; err != nil || user.Email != "dave@example.com" {
+		t.Fatalf("stored email %q, want dave@example.com", user.Email)
+	}
+
+	if w := api.do(http.MethodPost, "/auth/register", gin.H{"name": "Dave", "email": "DAVE@example.com", "password": "passw0rd"}); w.Code != http.StatusConflict {
+		t.Errorf("register the address in other case: %d %s", w.Code, w.Body)
+	}
+	if w := api.login(" DAVE@example.com", "passw0rd"); w.Code != http.StatusOK {
+		t.Errorf("login in other case: %d %s", w.Code, w.Body)
+	}
+	api.do(http.MethodPost, "/auth/forgot-password", gin.H{"email": "Dave@EXAMPLE.com"})
+	api.mailedToken("dave@example.com")
+
+	// The index catches addresses that bypass normalization
+	if err := database.DB.Create(&models.User{Name: "Dave", Email: "Dave@example.com"}).Error; err == nil {
+		t.Error("stored a second user with the address in other case")
+	}
+}
@@ -0,0 +1,128 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+	"zoom-meeting-app/database"
+	"zoom-meeting-app/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// List users (GET /admin/users), optionally filtered by ?role=
+func GetUsers(c *gin.Context) {
+	query := database.DB.Order("id")
+	if role := models.Role(c.Query("role")); role != "" {
+		if !role.Valid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "role must be one of admin, organizer, member or viewer"})
+			return
+		}
+		query = query.Where("role = ?", role)
+	}
+
+	var users []models.User
+	if err := query.Find(&users).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load users"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": users})
+}
+
+// Change a user's role (PUT /admin/users/:id/role)
+func UpdateUserRole(c *gin.Context) {
+	currentUser, ok := currentUserFromContext(c)
+	if !ok {
+		return
+	}
+
+	user, ok := findOtherUser(c, currentUser, "change your own role")
+	if !ok {
+		return
+	}
+
+	var input struct {
+		Role models.Role `json:"role"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !input.Role.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "role must be one of admin, organizer, member or viewer"})
+		return
+	}
+
+	user.Role = input.Role
+	if err := database.DB.Model(&user).Update("role", user.Role).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update role"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": user})
+}
+
+// Deactivate a user's account (POST /admin/users/:id/deactivate)
+func DeactivateUser(c *gin.Context) {
+	currentUser, ok := currentUserFromContext(c)
+	if !ok {
+		return
+	}
+
+	user, ok := findOtherUser(c, currentUser, "deactivate your own account")
+	if !ok {
+		return
+	}
+
+	// Sessions are revoked so they do not come back on reactivation
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if user.Active() {
+			now := time.Now()
+			user.DeactivatedAt = &now
+			if err := tx.Model(&user).Update("deactivated_at", user.DeactivatedAt).Error; err != nil {
+				return err
+			}
+		}
+		return revokeUserSessions(tx, user.ID)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to deactivate user"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": user})
+}
+
+// Reactivate a user's account (POST /admin/users/:id/reactivate)
+func ReactivateUser(c *gin.Context) {
+	var user models.User
+	if err := database.DB.First(&user, "id = ?", c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	if !user.Active() {
+		user.DeactivatedAt = nil
+		if err := database.DB.Model(&user).Update("deactivated_at", nil).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reactivate user"})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"data": user})
+}
+
+// findOtherUser loads the user named by the :id parameter, refusing the
+// admin's own account so they cannot lock themselves out. It writes an
+// error response and returns false when the user cannot be changed.
+func findOtherUser(c *gin.Context, currentUser models.User, action string) (models.User, bool) {
+	var user models.User
+	if err := database.DB.First(&user, "id = ?", c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return user, false
+	}
+
+	if user.ID == currentUser.ID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You cannot " + action})
+		return user, false
+	}
+	return user, true
+}
@@ -1,34 +1,63 @@
 package controllers
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
-	"os"
-	"time"
 	"zoom-meeting-app/database"
 	"zoom-meeting-app/models"
 	"zoom-meeting-app/utils"
+	"zoom-meeting-app/zoom"
 
 	"github.com/gin-gonic/gin"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
+// registerRequest is the body of POST /auth/register
+type registerRequest struct {
+	Name     string `json:"name" binding:"required,min=2,max=100"`
+	Email    string `json:"email" binding:"required,email,max=254"`
+	Password string `json:"password" binding:"required,min=8,password"`
+}
+
+func (r *registerRequest) normalize() {
+	r.Email = models.NormalizeEmail(r.Email)
+}
+
+// Register an account (POST /auth/register)
+//
+// Responds 409 when the email address is already used by another account.
 func Register(c *gin.Context) {
-	var input struct {
-		Name     string `json:"name"`
-		Email    string `json:"email"`
-		Password string `json:"password"`
+	var input registerRequest
+	if !bindJSON(c, &input) {
+		return
 	}
 
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to register user"})
 		return
 	}
 
-	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
-
 	user := models.User{Name: input.Name, Email: input.Email, Password: string(hashedPassword)}
-	database.DB.Create(&user)
+	if err := database.DB.Create(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Email is already registered"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to register user"})
+		return
+	}
+
+	sendInBackground("email verification", func(ctx context.Context) error {
+		if err := sendVerificationEmail(ctx, user); err != nil {
+			return fmt.Errorf("user %d: %w", user.ID, err)
+		}
+		return nil
+	})
 
 	c.JSON(http.StatusOK, gin.H{"message": "User registered"})
 }
@@ -45,7 +74,7 @@ func Login(c *gin.Context) {
 	}
 
 	var user models.User
-	if err := database.DB.Where("email = ?", input.Email).First(&user).Error; err != nil {
+	if err := database.DB.Where("email = ?", models.NormalizeEmail(input.Email)).First(&user).Error; err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid email or password"})
 		return
 	}
@@ -54,24 +83,31 @@ func Login(c *gin.Context) {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid email or password"})
 		return
 	}
+	if !user.Active() {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Account is deactivated"})
+		return
+	}
+	if appConfig.RequireVerifiedEmail && !user.EmailVerified() {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Email address is not verified"})
+		return
+	}
 
-	token, _ := utils.GenerateToken(user.ID)
-	// Store valid token in a secure HTTP cookie
-	c.SetCookie("accessToken", token, 3600, "/", "localhost", false, true)
+	tokens, err := issueTokens(database.DB, user, "")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to issue token"})
+		return
+	}
+	// Store valid tokens in secure HTTP cookies
+	setTokenCookies(c, tokens)
 	c.JSON(http.StatusOK, gin.H{
-		"data": gin.H{
-			"accessToken": token,
-		},
+		"data": tokens,
 	})
 
 }
 
 // RedirectToZoom handles the Zoom OAuth redirection
 func RedirectToZoom(c *gin.Context) {
-	zoomClientID := os.Getenv("ZOOM_CLIENT_ID")
-	zoomClientSecret := os.Getenv("ZOOM_CLIENT_SECRET") // Needed for refreshing tokens
-	redirectFrontend := os.Getenv("REDIRECT_FRONTEND")
-	redirectURI := "http://localhost:8000/auth/callback" // Backend callback URL
+	redirectFrontend := appConfig.RedirectFrontend
 
 	// Retrieve user from context
 	user, exists := c.Get("user")
@@ -87,32 +123,16 @@ func RedirectToZoom(c *gin.Context) {
 		return
 	}
 
-	// Check if user has a Zoom token
-	if currentUser.ZoomToken != "" {
-		// Verify if the Zoom token is still valid
-		if utils.IsValidZoomToken(currentUser.ZoomToken) {
-			c.Redirect(http.StatusFound, redirectFrontend)
-			return
-		}
-
-		// If token is invalid, try refreshing it
-		newToken, newRefreshToken, err := utils.RefreshZoomToken(currentUser.ZoomRefresh, zoomClientID, zoomClientSecret)
-		if err == nil {
-			// Update user with new tokens
-			currentUser.ZoomToken = newToken
-			currentUser.ZoomRefresh = newRefreshToken
-			database.DB.Save(&currentUser) // Save updated tokens to DB
-
+	// If the stored Zoom token is still usable (refreshing it when expired), skip the consent screen
+	if currentUser.ZoomRefresh != "" {
+		if _, err := utils.ZoomClient().Me(c.Request.Context(), currentUser.ID); err == nil {
 			c.Redirect(http.StatusFound, redirectFrontend)
 			return
 		}
 	}
+
 	// If refresh fails or user has no token, force re-authentication with Zoom
-	authURL := fmt.Sprintf(
-		"https://zoom.us/oauth/authorize?response_type=code&client_id=%s&redirect_uri=%s",
-		zoomClientID, redirectURI,
-	)
-	c.Redirect(http.StatusFound, authURL)
+	c.Redirect(http.StatusFound, utils.ZoomOAuth().AuthCodeURL())
 }
 
 func ZoomCallback(c *gin.Context) {
@@ -124,7 +144,7 @@ func ZoomCallback(c *gin.Context) {
 	}
 
 	// Exchange the code for an access token
-	tokenResponse, err := utils.ExchangeCodeForToken(code)
+	tokenResponse, err := utils.ZoomOAuth().Exchange(c.Request.Context(), code)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to exchange code"})
 		return
@@ -139,7 +159,7 @@ func ZoomCallback(c *gin.Context) {
 
 	// Validate the token
 	claims, err := utils.ValidateToken(cookieToken)
-	if err != nil {
+	if err != nil || models.TokenRevoked(database.DB, claims.ID) {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
 		c.Abort()
 		return
@@ -153,13 +173,13 @@ func ZoomCallback(c *gin.Context) {
 		return
 	}
 
-	// Fetch Zoom profile to get the email
-	zoomProfile, err := utils.GetZoomProfile(c, tokenResponse.AccessToken)
+	// Fetch Zoom profile with the new token, which is not stored yet
+	zoomProfile, err := utils.ZoomClient().WithTokens(zoom.StaticToken(tokenResponse.AccessToken)).Me(c.Request.Context(), user.ID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch Zoom profile"})
 		return
 	}
-	if user.IdZoom != "" && user.IdZoom != zoomProfile.Id {
+	if user.IdZoom != "" && user.IdZoom != zoomProfile.ID {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed connect to zoom, account zoom did not match with existing account apps, please use the first integrated same zoom account with your account apps"})
 		return
 	}
@@ -167,15 +187,54 @@ func ZoomCallback(c *gin.Context) {
 	database.DB.Model(&user).Updates(map[string]interface{}{
 		"zoom_token":   tokenResponse.AccessToken,
 		"zoom_refresh": tokenResponse.RefreshToken,
-		"zoom_expires": time.Now().Add(time.Duration(tokenResponse.ExpiresIn) * time.Second),
-		"id_zoom":      zoomProfile.Id,
+		"zoom_expires": tokenResponse.Expiry(),
+		"zoom_account": tokenResponse.AccountID,
+		"id_zoom":      zoomProfile.ID,
 	})
 
-	redirectFrontend := os.Getenv("REDIRECT_FRONTEND")
-	c.Redirect(http.StatusFound, redirectFrontend)
+	// Import the user's meetings now instead of waiting for the next sync round
+	if meetingSync != nil {
+		go func() {
+			if _, err := meetingSync.SyncUser(context.Background(), user.ID); err != nil {
+				log.Printf("meeting sync: user %d: %v", user.ID, err)
+			}
+		}()
+	}
+
+	c.Redirect(http.StatusFound, appConfig.RedirectFrontend)
 }
 
 func Me(c *gin.Context) {
 	user, _ := c.Get("user")
 	c.JSON(http.StatusOK, user)
 }
+
+// UpdateMe updates the current user's preferences (PATCH /auth/me)
+func UpdateMe(c *gin.Context) {
+	user, _ := c.Get("user")
+	currentUser, ok := user.(models.User)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "User type assertion failed"})
+		return
+	}
+
+	var input struct {
+		Timezone *string `json:"timezone"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if input.Timezone != nil {
+		if _, err := utils.LoadTimezone(*input.Timezone); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		currentUser.Timezone = *input.Timezone
+	}
+
+	database.DB.Model(&currentUser).Update("timezone", currentUser.Timezone)
+	c.JSON(http.StatusOK, currentUser)
+}
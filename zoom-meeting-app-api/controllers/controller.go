@@ -0,0 +1,38 @@
+package controllers
+
+import (
+	"net/http"
+	"zoom-meeting-app/config"
+	"zoom-meeting-app/meetingsync"
+	"zoom-meeting-app/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	appConfig   *config.Config
+	meetingSync *meetingsync.Service
+)
+
+// Init injects the application configuration and services into the controllers
+func Init(cfg *config.Config, sync *meetingsync.Service) {
+	appConfig = cfg
+	meetingSync = sync
+}
+
+// currentUserFromContext returns the user set by AuthMiddleware, writing an
+// error response and returning false when it is missing
+func currentUserFromContext(c *gin.Context) (models.User, bool) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return models.User{}, false
+	}
+
+	currentUser, ok := user.(models.User)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "User type assertion failed"})
+		return models.User{}, false
+	}
+	return currentUser, true
+}
@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"zoom-meeting-app/database"
+	"zoom-meeting-app/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// meetingETag returns the entity tag of a meeting's current version
+func meetingETag(meeting models.Meeting) string {
+	return `"` + strconv.FormatUint(uint64(meeting.Version), 10) + `"`
+}
+
+// checkIfMatch enforces the If-Match precondition of a meeting update. It
+// writes 428 when the header is missing or 412 when it names another version
+// and returns false in both cases.
+func checkIfMatch(c *gin.Context, meeting models.Meeting) bool {
+	header := c.GetHeader("If-Match")
+	if header == "" {
+		c.JSON(http.StatusPreconditionRequired, gin.H{"error": "If-Match header with the meeting's ETag is required"})
+		return false
+	}
+
+	current := meetingETag(meeting)
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == current {
+			return true
+		}
+	}
+
+	c.Header("ETag", current)
+	c.JSON(http.StatusPreconditionFailed, gin.H{"error": "Meeting has been modified, reload it and try again", "version": meeting.Version})
+	return false
+}
+
+// saveMeetingVersion stores the meeting only if it is still at the version it
+// was loaded with, and reports whether it was stored. Only the given columns
+// are written, or all of them when none are given. The version is bumped by
+// models.Meeting.BeforeUpdate.
+func saveMeetingVersion(meeting *models.Meeting, columns ...string) (bool, error) {
+	query := database.DB.Model(meeting).Where("version = ?", meeting.Version)
+	if len(columns) == 0 {
+		query = query.Select("*").Omit("CreatedAt")
+	} else {
+		query = query.Select(append(columns, "version"))
+	}
+	result := query.Updates(meeting)
+	return result.RowsAffected == 1, result.Error
+}
+
+// saveOrReloadMeeting stores the meeting with saveMeetingVersion. When it was
+// changed concurrently, e.g. by PUT or PATCH, the newer stored meeting is
+// loaded into meeting instead of being overwritten.
+func saveOrReloadMeeting(meeting *models.Meeting) error {
+	saved, err := saveMeetingVersion(meeting)
+	if err != nil || saved {
+		return err
+	}
+
+	var current models.Meeting
+	if err := database.DB.First(&current, meeting.ID).Error; err != nil {
+		return err
+	}
+	*meeting = current
+	return nil
+}
@@ -0,0 +1,14 @@
+package controllers
+
+import (
+	"net/http"
+	"zoom-meeting-app/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Public keys of the access token signing keys (GET /.well-known/jwks.json)
+func GetJWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, gin.H{"keys": utils.JWTKeys().JWKS()})
+}
@@ -0,0 +1,64 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+	"zoom-meeting-app/database"
+	"zoom-meeting-app/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// meetingAccess is how much of a meeting a user may manage
+type meetingAccess int
+
+const (
+	// meetingAccessNone hides the meeting from the user entirely
+	meetingAccessNone meetingAccess = iota
+	// meetingAccessEdit allows viewing and editing the meeting and its
+	// occurrences, as granted to alternative hosts
+	meetingAccessEdit
+	// meetingAccessOwner also allows deleting the meeting, as granted to its
+	// owner and to roles that manage every meeting
+	meetingAccessOwner
+)
+
+// meetingAccessFor returns the access user has to meeting. Alternative hosts
+// are matched by email address, so only verified addresses count; anyone
+// could otherwise register with a host's address before its owner does.
+func meetingAccessFor(user models.User, meeting models.Meeting) meetingAccess {
+	if meeting.UserID == user.ID || user.Can(models.PermissionManageMeetings) {
+		return meetingAccessOwner
+	}
+	if meeting.Settings != nil && user.Email != "" && user.EmailVerified() {
+		for _, host := range meeting.Settings.AlternativeHosts {
+			if strings.EqualFold(host, user.Email) {
+				return meetingAccessEdit
+			}
+		}
+	}
+	return meetingAccessNone
+}
+
+// findAuthorizedMeeting loads the meeting with the given Zoom ID and checks
+// that user has at least the required access. Meetings the user cannot see
+// are reported as 404 so their existence is not revealed; meetings they can
+// see but not manage as required are reported as 403.
+func findAuthorizedMeeting(c *gin.Context, user models.User, zoomID string, required meetingAccess) (models.Meeting, bool) {
+	var meeting models.Meeting
+	if err := database.DB.First(&meeting, "zoom_id = ?", zoomID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Meeting not found"})
+		return meeting, false
+	}
+
+	access := meetingAccessFor(user, meeting)
+	if access == meetingAccessNone {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Meeting not found"})
+		return meeting, false
+	}
+	if access < required {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the meeting owner can do this"})
+		return meeting, false
+	}
+	return meeting, true
+}
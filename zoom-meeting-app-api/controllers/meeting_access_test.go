@@ -0,0 +1,412 @@
+package controllers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+	"zoom-meeting-app/config"
+	"zoom-meeting-app/controllers"
+	"zoom-meeting-app/database"
+	"zoom-meeting-app/models"
+	"zoom-meeting-app/utils"
+	"zoom-meeting-app/zoom"
+	"zoom-meeting-app/zoom/zoomtest"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// testWebhookSecret signs the webhooks sent to a testAPI
+const testWebhookSecret = "webhook-secret"
+
+// testAPI serves the meeting routes against an in-memory database and a fake
+// Zoom server. Requests are authenticated as the user whose ID is in the
+// X-Test-User header.
+type testAPI struct {
+	t      *testing.T
+	router *gin.Engine
+	zoom   *zoomtest.Server
+	client *zoom.Client
+}
+
+func newTestAPI(t *testing.T) *testAPI {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	openTestDatabase(t)
+
+	srv := zoomtest.NewServer()
+	t.Cleanup(srv.Close)
+	token := srv.IssueToken(srv.DefaultUser().ID)
+	client := srv.NewClient(zoom.StaticToken(token.AccessToken))
+	utils.SetZoom(srv.OAuth("http://localhost/auth/callback"), client)
+
+	controllers.Init(&config.Config{Zoom: config.ZoomConfig{WebhookSecret: testWebhookSecret}}, nil)
+
+	router := gin.New()
+	// Zoom signs webhooks instead of authenticating as a user
+	router.POST("/webhooks/zoom", controllers.ZoomWebhook)
+	router.Use(func(c *gin.Context) {
+		var user models.User
+		if err := database.DB.First(&user, c.GetHeader("X-Test-User")).Error; err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+			return
+		}
+		c.Set("user", user)
+	})
+	router.POST("/meetings/", controllers.CreateMeeting)
+	router.GET("/meetings/", controllers.GetMeetings)
+	router.GET("/meetings/:id", controllers.GetMeetingByID)
+	router.PUT("/meetings/:id", controllers.UpdateMeeting)
+	router.PATCH("/meetings/:id", controllers.PatchMeeting)
+	router.DELETE("/meetings/:id", controllers.DeleteMeeting)
+	router.PUT("/meetings/:id/occurrences/:occurrence_id", controllers.UpdateOccurrence)
+	router.DELETE("/meetings/:id/occurrences/:occurrence_id", controllers.DeleteOccurrence)
+	router.POST("/templates/", controllers.CreateTemplate)
+	router.GET("/templates/", controllers.GetTemplates)
+	router.GET("/templates/:id", controllers.GetTemplateByID)
+	router.PUT("/templates/:id", controllers.UpdateTemplate)
+	router.DELETE("/templates/:id", controllers.DeleteTemplate)
+
+	return &testAPI{t: t, router: router, zoom: srv, client: client}
+}
+
+// openTestDatabase replaces database.DB with a migrated in-memory database
+// for the duration of the test
+func openTestDatabase(t *testing.T) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{
+		Logger:         logger.Default.LogMode(logger.Silent),
+		TranslateError: true,
+	})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	database.DB = db
+	database.MigrateDatabase()
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+}
+
+// createUser stores a user with the given role and a verified email address
+func (api *testAPI) createUser(name string, role models.Role) models.User {
+	api.t.Helper()
+	verifiedAt := time.Now()
+	user := models.User{Name: name, Email: name + "@example.com", Timezone: "UTC", Role: role, EmailVerifiedAt: &verifiedAt}
+	if err := database.DB.Create(&user).Error; err != nil {
+		api.t.Fatalf("create user: %v", err)
+	}
+	return user
+}
+
+func (api *testAPI) do(user models.User, method, path, body string) *httptest.ResponseRecorder {
+	api.t.Helper()
+	return api.doIfMatch(user, "", method, path, body)
+}
+
+// doIfMatch sends a request with the given If-Match header, if not empty
+func (api *testAPI) doIfMatch(user models.User, ifMatch, method, path, body string) *httptest.ResponseRecorder {
+	api.t.Helper()
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Test-User", strconv.FormatUint(uint64(user.ID), 10))
+	if ifMatch != "" {
+		req.Header.Set("If-Match", ifMatch)
+	}
+	w := httptest.NewRecorder()
+	api.router.ServeHTTP(w, req)
+	return w
+}
+
+// hookTransport runs after once Zoom has answered the first request to path,
+// as a request completing concurrently would
+type hookTransport struct {
+	base  http.RoundTripper
+	path  string
+	after func()
+	done  bool
+}
+
+func (h *hookTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	resp, err := h.base.RoundTrip(r)
+	if !h.done && r.URL.Path == h.path {
+		h.done = true
+		h.after()
+	}
+	return resp, err
+}
+
+// afterZoomRequest runs after once Zoom has answered the first API request
+// to path, e.g. "/v2/meetings/85000000000"
+func (api *testAPI) afterZoomRequest(path string, after func()) {
+	base := api.client.HTTPClient.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	api.client.HTTPClient.Transport = &hookTransport{base: base, path: path, after: after}
+}
+
+// createMeeting creates a meeting owned by owner and returns its Zoom ID
+func (api *testAPI) createMeeting(owner models.User, body string) string {
+	api.t.Helper()
+	w := api.do(owner, http.MethodPost, "/meetings/", body)
+	if w.Code != http.StatusOK {
+		api.t.Fatalf("create meeting: %d %s", w.Code, w.Body)
+	}
+	var response struct {
+		Data controllers.MeetingResponse `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		api.t.Fatalf("decode meeting: %v", err)
+	}
+	return response.Data.ZoomID
+}
+
+func TestMeetingRoutesHideOtherUsersMeetings(t *testing.T) {
+	api := newTestAPI(t)
+	owner := api.createUser("owner", models.RoleMember)
+	other := api.createUser("other", models.RoleMember)
+	id := api.createMeeting(owner, `{"topic":"Standup","start_time":"2030-01-07T09:00","recurrence":{"type":"daily","end_times":3}}`)
+
+	// Updates match any version, so only access decides the response
+	tests := []struct {
+		method, path, body, ifMatch string
+	}{
+		{http.MethodGet, "/meetings/" + id, "", ""},
+		{http.MethodPut, "/meetings/" + id, `{"topic":"Hijacked","start_time":"2030-01-07T09:00"}`, "*"},
+		{http.MethodPatch, "/meetings/" + id, `{"topic":"Hijacked"}`, "*"},
+		{http.MethodDelete, "/meetings/" + id, "", ""},
+		{http.MethodPut, "/meetings/" + id + "/occurrences/1894093200000", `{"duration":10}`, ""},
+		{http.MethodDelete, "/meetings/" + id + "/occurrences/1894093200000", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			w := api.doIfMatch(other, tt.ifMatch, tt.method, tt.path, tt.body)
+			if w.Code != http.StatusNotFound {
+				t.Fatalf("status = %d, want 404: %s", w.Code, w.Body)
+			}
+		})
+	}
+
+	var meeting models.Meeting
+	if err := database.DB.First(&meeting, "zoom_id = ?", id).Error; err != nil {
+		t.Fatalf("meeting was deleted: %v", err)
+	}
+	if meeting.Topic != "Standup" || meeting.Version != 1 {
+		t.Fatalf("meeting was modified: topic %q, version %d", meeting.Topic, meeting.Version)
+	}
+	var overrides int64
+	database.DB.Model(&models.MeetingOccurrence{}).Count(&overrides)
+	if overrides != 0 {
+		t.Fatalf("occurrence overrides = %d, want 0", overrides)
+	}
+}
+
+func TestAlternativeHostCanEditButNotDelete(t *testing.T) {
+	api := newTestAPI(t)
+	owner := api.createUser("owner", models.RoleMember)
+	host := api.createUser("host", models.RoleMember)
+	id := api.createMeeting(owner, `{"topic":"Retro","start_time":"2030-01-07T09:00","settings":{"alternative_hosts":["HOST@example.com"]}}`)
+
+	w := api.do(host, http.MethodGet, "/meetings/"+id, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("get: status = %d, want 200: %s", w.Code, w.Body)
+	}
+	if w := api.doIfMatch(host, w.Header().Get("ETag"), http.MethodPatch, "/meetings/"+id, `{"topic":"Sprint retro"}`); w.Code != http.StatusOK {
+		t.Fatalf("patch: status = %d, want 200: %s", w.Code, w.Body)
+	}
+	if w := api.do(host, http.MethodDelete, "/meetings/"+id, ""); w.Code != http.StatusForbidden {
+		t.Fatalf("delete: status = %d, want 403: %s", w.Code, w.Body)
+	}
+
+	var meeting models.Meeting
+	if err := database.DB.First(&meeting, "zoom_id = ?", id).Error; err != nil {
+		t.Fatalf("meeting was deleted: %v", err)
+	}
+	if meeting.Topic != "Sprint retro" || meeting.UserID != owner.ID {
+		t.Fatalf("meeting = %q owned by %d, want the edit kept and owner %d", meeting.Topic, meeting.UserID, owner.ID)
+	}
+}
+
+func TestUnverifiedAlternativeHostHasNoAccess(t *testing.T) {
+	api := newTestAPI(t)
+	owner := api.createUser("owner", models.RoleMember)
+	id := api.createMeeting(owner, `{"topic":"Retro","start_time":"2030-01-07T09:00","settings":{"alternative_hosts":["host@example.com"]}}`)
+
+	// Registered with the host's address, which nobody has verified
+	impostor := api.createUser("host", models.RoleMember)
+	if err := database.DB.Model(&impostor).Update("email_verified_at", nil).Error; err != nil {
+		t.Fatal(err)
+	}
+	impostor.EmailVerifiedAt = nil
+
+	if w := api.do(impostor, http.MethodGet, "/meetings/"+id, ""); w.Code != http.StatusNotFound {
+		t.Fatalf("get: status = %d, want 404: %s", w.Code, w.Body)
+	}
+	if w := api.doIfMatch(impostor, "*", http.MethodPatch, "/meetings/"+id, `{"start_time":"2030-02-01T09:00"}`); w.Code != http.StatusNotFound {
+		t.Fatalf("patch: status = %d, want 404: %s", w.Code, w.Body)
+	}
+
+	var meeting models.Meeting
+	database.DB.First(&meeting, "zoom_id = ?", id)
+	if !meeting.StartTime.Equal(time.Date(2030, 1, 7, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("meeting moved to %v", meeting.StartTime)
+	}
+}
+
+func TestOwnerAndAdminCanDelete(t *testing.T) {
+	api := newTestAPI(t)
+	owner := api.createUser("owner", models.RoleMember)
+	admin := api.createUser("admin", models.RoleAdmin)
+
+	for _, user := range []models.User{owner, admin} {
+		id := api.createMeeting(owner, `{"topic":"Planning","start_time":"2030-01-07T09:00"}`)
+		if w := api.do(user, http.MethodDelete, "/meetings/"+id, ""); w.Code != http.StatusOK {
+			t.Fatalf("delete as %s: status = %d, want 200: %s", user.Name, w.Code, w.Body)
+		}
+		if err := database.DB.First(&models.Meeting{}, "zoom_id = ?", id).Error; err == nil {
+			t.Fatalf("delete as %s: meeting still exists", user.Name)
+		}
+	}
+}
+
+func TestGetMeetingByIDImportsOnlyOwnZoomMeetings(t *testing.T) {
+	api := newTestAPI(t)
+	owner := api.createUser("owner", models.RoleMember)
+	other := api.createUser("other", models.RoleMember)
+	database.DB.Model(&owner).Update("id_zoom", api.zoom.DefaultUser().ID)
+	database.DB.Model(&other).Update("id_zoom", "another-zoom-user")
+
+	// Created directly in Zoom, so there is no local row yet
+	created, err := api.client.CreateMeeting(context.Background(), owner.ID, &zoom.MeetingRequest{
+		Topic:     "Created in Zoom",
+		Type:      zoom.MeetingTypeScheduled,
+		StartTime: "2030-01-07T09:00:00Z",
+		Duration:  30,
+	})
+	if err != nil {
+		t.Fatalf("CreateMeeting: %v", err)
+	}
+	id := created.ID.String()
+
+	if w := api.do(other, http.MethodGet, "/meetings/"+id, ""); w.Code != http.StatusNotFound {
+		t.Fatalf("get as other: status = %d, want 404: %s", w.Code, w.Body)
+	}
+	var count int64
+	database.DB.Model(&models.Meeting{}).Count(&count)
+	if count != 0 {
+		t.Fatalf("meetings stored after another user's request = %d, want 0", count)
+	}
+
+	if w := api.do(owner, http.MethodGet, "/meetings/"+id, ""); w.Code != http.StatusOK {
+		t.Fatalf("get as owner: status = %d, want 200: %s", w.Code, w.Body)
+	}
+	var meeting models.Meeting
+	if err := database.DB.First(&meeting, "zoom_id = ?", id).Error; err != nil || meeting.UserID != owner.ID {
+		t.Fatalf("meeting not imported for the owner: %+v, %v", meeting, err)
+	}
+}
+
+func TestMeetingUpdatesRequireCurrentETag(t *testing.T) {
+	api := newTestAPI(t)
+	owner := api.createUser("owner", models.RoleMember)
+	id := api.createMeeting(owner, `{"topic":"Standup","start_time":"2030-01-07T09:00"}`)
+	path := "/meetings/" + id
+
+	w := api.do(owner, http.MethodGet, path, "")
+	etag := w.Header().Get("ETag")
+	if w.Code != http.StatusOK || etag == "" {
+		t.Fatalf("get: status = %d, ETag %q; want 200 and an ETag", w.Code, etag)
+	}
+
+	updates := []struct {
+		method, body string
+	}{
+		{http.MethodPut, `{"topic":"Edited","start_time":"2030-01-07T09:00"}`},
+		{http.MethodPatch, `{"topic":"Edited"}`},
+	}
+	for _, update := range updates {
+		t.Run(update.method, func(t *testing.T) {
+			tests := []struct {
+				name    string
+				ifMatch string
+				status  int
+			}{
+				{"missing", "", http.StatusPreconditionRequired},
+				{"stale", `"0"`, http.StatusPreconditionFailed},
+				{"stale weak", `W/"0"`, http.StatusPreconditionFailed},
+				{"stale list", `"0", "999"`, http.StatusPreconditionFailed},
+			}
+			for _, tt := range tests {
+				w := api.doIfMatch(owner, tt.ifMatch, update.method, path, update.body)
+				if w.Code != tt.status {
+					t.Errorf("%s If-Match: status = %d, want %d: %s", tt.name, w.Code, tt.status, w.Body)
+				}
+				if tt.status == http.StatusPreconditionFailed && w.Header().Get("ETag") != etag {
+					t.Errorf("%s If-Match: ETag = %q, want the current %q", tt.name, w.Header().Get("ETag"), etag)
+				}
+			}
+
+			var meeting models.Meeting
+			database.DB.First(&meeting, "zoom_id = ?", id)
+			if meeting.Topic != "Standup" {
+				t.Fatalf("meeting updated without the current ETag: topic %q", meeting.Topic)
+			}
+		})
+	}
+
+	// The current ETag is accepted once; the update makes it stale
+	w = api.doIfMatch(owner, etag, http.MethodPatch, path, `{"topic":"Edited"}`)
+	if w.Code != http.StatusOK || w.Header().Get("ETag") == etag {
+		t.Fatalf("patch with the current ETag: status = %d, ETag %q: %s", w.Code, w.Header().Get("ETag"), w.Body)
+	}
+	if w := api.doIfMatch(owner, etag, http.MethodPatch, path, `{"topic":"Lost update"}`); w.Code != http.StatusPreconditionFailed {
+		t.Fatalf("patch with the old ETag: status = %d, want 412: %s", w.Code, w.Body)
+	}
+}
+
+func TestGetMeetingDoesNotOverwriteConcurrentEdits(t *testing.T) {
+	api := newTestAPI(t)
+	owner := api.createUser("owner", models.RoleMember)
+	id := api.createMeeting(owner, `{"topic":"Standup","start_time":"2030-01-07T09:00"}`)
+	path := "/meetings/" + id
+	api.do(owner, http.MethodGet, path, "")
+
+	// Renamed in Zoom, so the GET refreshes the stored meeting
+	if err := api.client.PatchMeeting(context.Background(), owner.ID, id, map[string]interface{}{"topic": "Renamed in Zoom"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A PATCH through the API saves its change while the GET waits for Zoom
+	var edited models.Meeting
+	api.afterZoomRequest("/v2/meetings/"+id, func() {
+		database.DB.First(&edited, "zoom_id = ?", id)
+		edited.Topic = "Edited"
+		if err := database.DB.Save(&edited).Error; err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	w := api.do(owner, http.MethodGet, path, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("get: %d %s", w.Code, w.Body)
+	}
+	var meeting models.Meeting
+	database.DB.First(&meeting, "zoom_id = ?", id)
+	if meeting.Topic != "Edited" || meeting.Version != edited.Version {
+		t.Errorf("meeting = topic %q, version %d; want the edit kept at version %d", meeting.Topic, meeting.Version, edited.Version)
+	}
+	if etag := w.Header().Get("ETag"); etag != `"`+strconv.FormatUint(uint64(edited.Version), 10)+`"` {
+		t.Errorf("ETag = %s, want the edited version %d", etag, edited.Version)
+	}
+}
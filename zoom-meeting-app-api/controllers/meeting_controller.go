@@ -1,14 +1,17 @@
 package controllers
 
 import (
-	"fmt"
+	"errors"
 	"net/http"
+	"reflect"
 	"time"
 	"zoom-meeting-app/database"
 	"zoom-meeting-app/models"
 	"zoom-meeting-app/utils"
+	"zoom-meeting-app/zoom"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // Define a custom struct for the response without UserID and User fields
@@ -16,189 +19,162 @@ type MeetingResponse struct {
 	ID        uint   `json:"ID"`
 	ZoomID    string `json:"zoom_id"`
 	Topic     string `json:"topic"`
-	StartTime string `json:"start_time"`
+	StartTime string `json:"start_time"` // Rendered in the requested time zone
+	EndTime   string `json:"end_time"`   // Rendered in the requested time zone
+	Duration  int    `json:"duration"`   // Minutes
+	Timezone  string `json:"timezone"`   // Time zone the meeting is scheduled in
 	JoinURL   string `json:"join_url"`
+	Status    string `json:"status"`  // waiting, started or ended
+	Version   uint   `json:"version"` // Also sent as the ETag of GET /meetings/:id
+
+	Type        int                     `json:"type"`
+	Recurrence  *models.Recurrence      `json:"recurrence,omitempty"`
+	Occurrences []OccurrenceResponse    `json:"occurrences,omitempty"`
+	Password    string                  `json:"password,omitempty"`
+	Settings    *models.MeetingSettings `json:"settings,omitempty"`
+	Agenda      string                  `json:"agenda,omitempty"`
+	Invitees    []string                `json:"invitees,omitempty"`
 }
 
+// maxMeetingDuration is the longest meeting duration accepted, in minutes
+const maxMeetingDuration = 24 * 60
+
 // Get All Meetings (GET /meetings)
 func GetMeetings(c *gin.Context) {
 	// Retrieve the current authenticated user from context
-	user, exists := c.Get("user")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+	currentUser, ok := currentUserFromContext(c)
+	if !ok {
 		return
 	}
 
-	currentUser, ok := user.(models.User)
+	loc, ok := displayLocation(c, currentUser)
 	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "User type assertion failed"})
 		return
 	}
 
-	// Fetch meetings from Zoom API
-	zoomMeetings, err := utils.GetZoomMeetings(c)
+	listType := c.DefaultQuery("type", zoom.ListScheduled)
+	if !zoom.ValidListType(listType) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "type must be one of scheduled, live, upcoming, upcoming_meetings or previous_meetings"})
+		return
+	}
+
+	params, err := parseMeetingListParams(c.Query, loc)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Get all meetings from database
-	var dbMeetings []models.Meeting
-	database.DB.Select("ID", "zoom_id, topic, start_time, join_url").Where("user_id = ?", currentUser.ID).Find(&dbMeetings)
+	// Meetings are kept in sync with Zoom in the background, see meetingsync
+	query := database.DB.Model(&models.Meeting{}).Where("user_id = ?", currentUser.ID)
+	query = params.filter(filterByListType(query, listType, time.Now())).Session(&gorm.Session{})
 
-	// Create a map to track database meetings
-	dbMeetingsMap := make(map[string]models.Meeting)
-	for _, meeting := range dbMeetings {
-		dbMeetingsMap[meeting.ZoomID] = meeting
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list meetings"})
+		return
 	}
 
-	// Process each Zoom meeting
-	for _, zoomMeeting := range zoomMeetings {
-		zoomID := fmt.Sprintf("%.0f", zoomMeeting["id"].(float64)) // Convert float64 to string
-
-		// Parse the start_time string to time.Time
-		zoomStartTime, err := time.Parse(time.RFC3339, zoomMeeting["start_time"].(string)) // Assuming start_time is in RFC3339 format
-		if err != nil {
-			// Handle error if time parsing fails
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid time format"})
-			return
-		}
+	var meetings []models.Meeting
+	params.paginate(query).Select("ID", "zoom_id, topic, type, recurrence, start_time, duration, timezone, join_url, status, version").Find(&meetings)
 
-		// Add 7 hours to the parsed time
-		adjustedStartTime := zoomStartTime.Add(7 * time.Hour)
-
-		// Format the adjusted time back to string
-		adjustedStartTimeStr := adjustedStartTime.Format(time.RFC3339)
-
-		if dbMeeting, exists := dbMeetingsMap[zoomID]; exists {
-			// If exists, check for updates
-			if dbMeeting.Topic != zoomMeeting["topic"].(string) ||
-				dbMeeting.StartTime != adjustedStartTimeStr ||
-				dbMeeting.JoinURL != zoomMeeting["join_url"].(string) {
-
-				// Update existing record
-				dbMeeting.Topic = zoomMeeting["topic"].(string)
-				dbMeeting.StartTime = adjustedStartTimeStr
-				dbMeeting.JoinURL = zoomMeeting["join_url"].(string)
-				database.DB.Save(&dbMeeting)
-			}
-		} else {
-			// If not exists, create new record
-			newMeeting := models.Meeting{
-				ZoomID:    zoomID,
-				Topic:     zoomMeeting["topic"].(string),
-				StartTime: adjustedStartTimeStr,
-				JoinURL:   zoomMeeting["join_url"].(string),
-				UserID:    currentUser.ID,
-			}
-			database.DB.Create(&newMeeting)
-		}
-	}
-	if len(zoomMeetings) > 0 {
-		// Return the latest meetings from the database
-		var updatedMeetings []models.Meeting
-		database.DB.Select("ID", "zoom_id, topic, start_time, join_url").Where("user_id = ?", currentUser.ID).Find(&updatedMeetings)
-		// Create a new slice for the response
-		var meetingResponses []MeetingResponse
-		for _, meeting := range dbMeetings {
-			meetingResponses = append(meetingResponses, MeetingResponse{
-				ID:        meeting.ID,
-				ZoomID:    meeting.ZoomID,
-				Topic:     meeting.Topic,
-				StartTime: meeting.StartTime,
-				JoinURL:   meeting.JoinURL,
-			})
-		}
-		c.JSON(http.StatusOK, gin.H{
-			"data": meetingResponses,
-		})
-	} else {
-		c.JSON(http.StatusOK, gin.H{
-			"data": nil,
-		})
+	meetingResponses := make([]MeetingResponse, 0, len(meetings))
+	for _, meeting := range meetings {
+		meetingResponses = append(meetingResponses, newMeetingResponse(meeting, loc))
 	}
+	c.JSON(http.StatusOK, gin.H{
+		"data": meetingResponses,
+		"meta": params.meta(total),
+	})
 }
 
 // Get Meeting by ID (GET /meetings/:id)
 func GetMeetingByID(c *gin.Context) {
 	// Retrieve the current authenticated user from context
-	user, exists := c.Get("user")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+	currentUser, ok := currentUserFromContext(c)
+	if !ok {
 		return
 	}
 
-	currentUser, ok := user.(models.User)
+	loc, ok := displayLocation(c, currentUser)
 	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "User type assertion failed"})
 		return
 	}
 
 	id := c.Param("id")
 
-	// Fetch meeting from Zoom API
-	zoomMeeting, err := utils.GetZoomMeetingByID(c, id)
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Meeting not found in Zoom API"})
+	// Check if meeting exists in database and the user may see it. Meetings
+	// not stored yet are fetched as the current user.
+	var dbMeeting models.Meeting
+	stored := database.DB.Where("zoom_id = ?", id).First(&dbMeeting).Error == nil
+	if stored && meetingAccessFor(currentUser, dbMeeting) == meetingAccessNone {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Meeting not found"})
 		return
 	}
-	// Parse the start_time string to time.Time
-	zoomStartTime, err := time.Parse(time.RFC3339, zoomMeeting["start_time"].(string)) // Assuming start_time is in RFC3339 format
-	if err != nil {
-		// Handle error if time parsing fails
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid time format"})
-		return
+	owner := currentUser.ID
+	if stored {
+		owner = dbMeeting.UserID
 	}
 
-	// Add 7 hours to the parsed time
-	adjustedStartTime := zoomStartTime.Add(7 * time.Hour)
-
-	// Format the adjusted time back to string
-	adjustedStartTimeStr := adjustedStartTime.Format(time.RFC3339)
-	// Check if meeting exists in database
-	var dbMeeting models.Meeting
-	if err := database.DB.Where("zoom_id = ?", id).First(&dbMeeting).Error; err != nil {
-		// Create new record if not exists
-		newMeeting := models.Meeting{
-			ZoomID:    id,
-			Topic:     zoomMeeting["topic"].(string),
-			StartTime: adjustedStartTimeStr,
-			JoinURL:   zoomMeeting["join_url"].(string),
-			UserID:    currentUser.ID,
+	// Fetch meeting from Zoom API
+	zoomMeeting, err := utils.ZoomClient().GetMeeting(c.Request.Context(), owner, id)
+	if err != nil {
+		if zoom.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Meeting not found in Zoom API"})
+			return
 		}
-		database.DB.Create(&newMeeting)
-		c.JSON(http.StatusOK, newMeeting)
+		respondZoomError(c, err)
 		return
 	}
 
-	// Update existing meeting
-	updated := false
-	if dbMeeting.Topic != zoomMeeting["topic"].(string) {
-		dbMeeting.Topic = zoomMeeting["topic"].(string)
-		updated = true
-	}
-	if dbMeeting.StartTime != adjustedStartTimeStr {
-		dbMeeting.StartTime = adjustedStartTimeStr
-		updated = true
-	}
-	if dbMeeting.JoinURL != zoomMeeting["join_url"].(string) {
-		dbMeeting.JoinURL = zoomMeeting["join_url"].(string)
-		updated = true
-	}
+	if !stored {
+		// Only import meetings hosted by the user's own Zoom account, not
+		// those of other users on the same account
+		if currentUser.IdZoom == "" || zoomMeeting.HostID != currentUser.IdZoom {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Meeting not found"})
+			return
+		}
 
-	if updated {
-		database.DB.Save(&dbMeeting)
+		// Create new record if not exists
+		dbMeeting = models.Meeting{ZoomID: id, UserID: currentUser.ID}
+		applyZoomMeeting(&dbMeeting, zoomMeeting)
+		created, err := dbMeeting.CreateIfAbsent(database.DB)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save meeting"})
+			return
+		}
+		if !created {
+			// Imported concurrently by the sync or a webhook
+			database.DB.Where("zoom_id = ?", id).First(&dbMeeting)
+		}
+	} else if applyZoomMeeting(&dbMeeting, zoomMeeting) {
+		// Update existing meeting
+		if err := saveOrReloadMeeting(&dbMeeting); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save meeting"})
+			return
+		}
 	}
 
+	c.Header("ETag", meetingETag(dbMeeting))
+	response := newMeetingResponse(dbMeeting, loc)
+	response.Occurrences = withOccurrenceOverrides(newOccurrenceResponses(zoomMeeting.Occurrences, loc), dbMeeting, loc)
 	c.JSON(http.StatusOK, gin.H{
-		"data": dbMeeting,
+		"data": response,
 	})
 }
 
 func CreateMeeting(c *gin.Context) {
 	var input struct {
-		Topic     string `json:"topic"`
-		StartTime string `json:"start_time"`
+		TemplateID *uint  `json:"template_id"` // Template whose defaults fill in omitted fields
+		Topic      string `json:"topic"`
+		StartTime  string `json:"start_time"`
+		Duration   int    `json:"duration"` // Minutes, defaults to 30
+		Timezone   string `json:"timezone"`
+		Agenda     string `json:"agenda"`
+
+		Recurrence *models.Recurrence      `json:"recurrence"` // Makes the meeting recurring when set
+		Password   string                  `json:"password"`
+		Settings   *models.MeetingSettings `json:"settings"`
+		Invitees   []string                `json:"invitees"`
 	}
 
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -206,62 +182,123 @@ func CreateMeeting(c *gin.Context) {
 		return
 	}
 
-	zoomResponse, err := utils.CreateZoomMeeting(c, input.Topic, input.StartTime)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	// Retrieve the current authenticated user from context
+	currentUser, ok := currentUserFromContext(c)
+	if !ok {
 		return
 	}
 
-	// Safely extract Zoom ID
-	idFloat, ok := zoomResponse["id"].(float64)
-	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid Zoom ID format"})
+	var template models.MeetingTemplate
+	if input.TemplateID != nil {
+		if template, ok = findTemplate(c, currentUser.ID, *input.TemplateID); !ok {
+			return
+		}
+	}
+
+	duration := input.Duration
+	if duration == 0 {
+		duration = template.Duration
+	}
+	if duration == 0 {
+		duration = models.DefaultMeetingDuration
+	}
+	if !validDuration(c, duration) {
 		return
 	}
-	zoomID := fmt.Sprintf("%.0f", idFloat) // Convert float64 to string
 
-	// Safely extract Join URL
-	joinURL, ok := zoomResponse["join_url"].(string)
-	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid join URL format"})
+	// The meeting is scheduled in the requested time zone, falling back to the template's and then the user's preference
+	timezone := firstNonEmpty(input.Timezone, template.Timezone, currentUser.Timezone, utils.DefaultTimezone)
+	meetingLoc, err := utils.LoadTimezone(timezone)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Retrieve the current authenticated user from context
-	user, exists := c.Get("user")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+	startTime, err := utils.ParseStartTime(input.StartTime, meetingLoc)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	currentUser, ok := user.(models.User)
+	loc, ok := displayLocation(c, currentUser)
 	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "User type assertion failed"})
 		return
 	}
 
+	invitees := template.Invitees
+	if input.Invitees != nil {
+		if invitees, err = normalizeEmails("invitees", input.Invitees); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
 	// Create meeting and associate with the current user
 	meeting := models.Meeting{
-		ZoomID:    zoomID,
-		Topic:     input.Topic,
-		StartTime: input.StartTime,
-		JoinURL:   joinURL,
+		Topic:     firstNonEmpty(input.Topic, renderTopic(template.TopicPattern, startTime.In(meetingLoc))),
+		Type:      zoom.MeetingTypeScheduled,
+		StartTime: startTime,
+		Duration:  duration,
+		Timezone:  timezone,
+		Agenda:    firstNonEmpty(input.Agenda, template.Agenda),
+		Settings:  template.Settings,
+		Invitees:  invitees,
 		UserID:    currentUser.ID,
 	}
+	if !applyRecurrence(c, &meeting, input.Recurrence) || !applySettings(c, &meeting, &input.Password, input.Settings) {
+		return
+	}
 
-	database.DB.Create(&meeting)
+	zoomMeeting, err := utils.ZoomClient().CreateMeeting(c.Request.Context(), currentUser.ID, newZoomMeetingRequest(&meeting))
+	if err != nil {
+		respondZoomError(c, err)
+		return
+	}
+
+	meeting.ZoomID = zoomMeeting.ID.String()
+	meeting.JoinURL = zoomMeeting.JoinURL
+
+	// The meeting.created webhook may have stored the meeting already
+	created, err := meeting.CreateIfAbsent(database.DB)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save meeting"})
+		return
+	}
+	if !created {
+		var existing models.Meeting
+		if err := database.DB.Where("zoom_id = ?", meeting.ZoomID).First(&existing).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save meeting"})
+			return
+		}
+		meeting.Model = existing.Model
+		meeting.Version = existing.Version
+		if err := saveOrReloadMeeting(&meeting); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save meeting"})
+			return
+		}
+	}
 	c.JSON(http.StatusOK, gin.H{
-		"data": meeting,
+		"data": newMeetingResponse(meeting, loc),
 	})
 }
 
 func UpdateMeeting(c *gin.Context) {
 	id := c.Param("id")
 
+	// Retrieve the current authenticated user from context
+	currentUser, ok := currentUserFromContext(c)
+	if !ok {
+		return
+	}
+
 	// Find meeting in database
-	var meeting models.Meeting
-	if err := database.DB.First(&meeting, "zoom_id = ?", id).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Meeting not found"})
+	meeting, ok := findAuthorizedMeeting(c, currentUser, id, meetingAccessEdit)
+	if !ok {
+		return
+	}
+
+	// Refuse to overwrite changes the client has not seen
+	if !checkIfMatch(c, meeting) {
 		return
 	}
 
@@ -269,6 +306,14 @@ func UpdateMeeting(c *gin.Context) {
 	var input struct {
 		Topic     string `json:"topic"`
 		StartTime string `json:"start_time"`
+		Duration  int    `json:"duration"` // Minutes, keeps the current duration when omitted
+		Timezone  string `json:"timezone"`
+
+		Recurrence *models.Recurrence      `json:"recurrence"` // Omitting it turns the meeting into a single one
+		Password   *string                 `json:"password"`   // Keeps the current passcode when omitted
+		Settings   *models.MeetingSettings `json:"settings"`   // Only the fields given are changed
+		Agenda     *string                 `json:"agenda"`     // Keeps the current agenda when omitted
+		Invitees   []string                `json:"invitees"`   // Keeps the current invitees when omitted
 	}
 
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -276,39 +321,290 @@ func UpdateMeeting(c *gin.Context) {
 		return
 	}
 
-	// Update Zoom meeting
-	_, err := utils.UpdateZoomMeeting(c, id, input.Topic, input.StartTime)
+	if input.Duration == 0 {
+		input.Duration = meeting.Duration
+	}
+	if !validDuration(c, input.Duration) {
+		return
+	}
+
+	// Keep the meeting's time zone unless the request moves it to another one
+	timezone := firstNonEmpty(input.Timezone, meeting.Timezone, currentUser.Timezone, utils.DefaultTimezone)
+	meetingLoc, err := utils.LoadTimezone(timezone)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	startTime, err := utils.ParseStartTime(input.StartTime, meetingLoc)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	loc, ok := displayLocation(c, currentUser)
+	if !ok {
 		return
 	}
 
-	// Update meeting in database
 	meeting.Topic = input.Topic
-	meeting.StartTime = input.StartTime
-	database.DB.Save(&meeting)
+	meeting.StartTime = startTime
+	meeting.Duration = input.Duration
+	meeting.Timezone = timezone
+	if !applyRecurrence(c, &meeting, input.Recurrence) || !applySettings(c, &meeting, input.Password, input.Settings) {
+		return
+	}
+	if input.Agenda != nil {
+		meeting.Agenda = *input.Agenda
+	}
+	if input.Invitees != nil {
+		if meeting.Invitees, err = normalizeEmails("invitees", input.Invitees); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	// Update Zoom meeting
+	if err := utils.ZoomClient().UpdateMeeting(c.Request.Context(), meeting.UserID, id, newZoomMeetingRequest(&meeting)); err != nil {
+		respondZoomError(c, err)
+		return
+	}
 
-	c.JSON(http.StatusOK, meeting)
+	// Update meeting in database, unless it changed while Zoom was updated.
+	// Zoom discards the changes made to single occurrences when the whole
+	// series is edited.
+	saved, err := saveMeetingVersion(&meeting)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save meeting"})
+		return
+	}
+	if !saved {
+		c.JSON(http.StatusPreconditionFailed, gin.H{"error": "Meeting has been modified, reload it and try again"})
+		return
+	}
+	if err := models.ClearOccurrences(database.DB, meeting.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save meeting"})
+		return
+	}
+
+	c.Header("ETag", meetingETag(meeting))
+	c.JSON(http.StatusOK, newMeetingResponse(meeting, loc))
 }
 
 // Delete Meeting (DELETE /meetings/:id)
 func DeleteMeeting(c *gin.Context) {
 	id := c.Param("id")
 
+	currentUser, ok := currentUserFromContext(c)
+	if !ok {
+		return
+	}
+
 	// Find in Database
-	var meeting models.Meeting
-	if err := database.DB.First(&meeting, "zoom_id = ?", id).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Meeting not found"})
+	meeting, ok := findAuthorizedMeeting(c, currentUser, id, meetingAccessOwner)
+	if !ok {
 		return
 	}
 
 	// Delete from Zoom
-	if err := utils.DeleteZoomMeeting(c, id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := utils.ZoomClient().DeleteMeeting(c.Request.Context(), meeting.UserID, id); err != nil {
+		respondZoomError(c, err)
 		return
 	}
 
 	// Delete from Database
-	database.DB.Delete(&meeting)
+	if err := models.ClearOccurrences(database.DB, meeting.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete meeting"})
+		return
+	}
+	meeting.SoftDelete(database.DB, models.DeletedByUser)
 	c.JSON(http.StatusOK, gin.H{"message": "Meeting deleted"})
 }
+
+// filterByListType restricts a meeting query the way Zoom's list type filter
+// does. Recurring meetings count as upcoming, as later occurrences may follow.
+func filterByListType(query *gorm.DB, listType string, now time.Time) *gorm.DB {
+	switch listType {
+	case zoom.ListLive:
+		return query.Where("status = ?", models.MeetingStatusStarted)
+	case zoom.ListUpcoming, zoom.ListUpcomingMeetings:
+		return query.Where("start_time >= ? OR type = ? OR status = ?", now, zoom.MeetingTypeRecurringFixed, models.MeetingStatusStarted)
+	case zoom.ListPreviousMeetings:
+		return query.Where("start_time < ? AND type <> ? AND status <> ?", now, zoom.MeetingTypeRecurringFixed, models.MeetingStatusStarted)
+	}
+	return query
+}
+
+// applyZoomMeeting copies the fields Zoom owns onto a stored meeting and
+// reports whether anything changed
+func applyZoomMeeting(meeting *models.Meeting, zoomMeeting *zoom.Meeting) bool {
+	// Zoom returns start times in UTC
+	startTime := zoomMeeting.StartTime.UTC()
+	recurrence := fromZoomRecurrence(zoomMeeting.Recurrence)
+	settings := fromZoomSettings(zoomMeeting.Settings)
+
+	updated := meeting.Topic != zoomMeeting.Topic ||
+		meeting.Type != zoomMeeting.Type ||
+		!reflect.DeepEqual(meeting.Recurrence, recurrence) ||
+		!reflect.DeepEqual(meeting.Settings, settings) ||
+		meeting.Password != zoomMeeting.Password ||
+		meeting.Agenda != zoomMeeting.Agenda ||
+		!meeting.StartTime.Equal(startTime) ||
+		meeting.Duration != zoomMeeting.Duration ||
+		meeting.Timezone != zoomMeeting.Timezone ||
+		meeting.JoinURL != zoomMeeting.JoinURL
+
+	meeting.Topic = zoomMeeting.Topic
+	meeting.Type = zoomMeeting.Type
+	meeting.Recurrence = recurrence
+	meeting.Settings = settings
+	meeting.Password = zoomMeeting.Password
+	meeting.Agenda = zoomMeeting.Agenda
+	meeting.StartTime = startTime
+	meeting.Duration = zoomMeeting.Duration
+	meeting.Timezone = zoomMeeting.Timezone
+	meeting.JoinURL = zoomMeeting.JoinURL
+	return updated
+}
+
+// newZoomMeetingRequest builds the Zoom payload for a meeting. The start time
+// is sent in UTC; Zoom uses the time zone for display, invitations and recurrence.
+func newZoomMeetingRequest(meeting *models.Meeting) *zoom.MeetingRequest {
+	settings := toZoomSettings(meeting.Settings)
+	if len(meeting.Invitees) > 0 {
+		if settings == nil {
+			settings = &zoom.MeetingSettings{}
+		}
+		for _, email := range meeting.Invitees {
+			settings.MeetingInvitees = append(settings.MeetingInvitees, zoom.Invitee{Email: email})
+		}
+	}
+
+	return &zoom.MeetingRequest{
+		Topic:      meeting.Topic,
+		Type:       meeting.Type,
+		StartTime:  meeting.StartTime.UTC().Format(time.RFC3339),
+		Duration:   meeting.Duration,
+		Timezone:   meeting.Timezone,
+		Agenda:     meeting.Agenda,
+		Password:   meeting.Password,
+		Recurrence: toZoomRecurrence(meeting.Recurrence),
+		Settings:   settings,
+	}
+}
+
+// applyRecurrence validates the recurrence rule and sets it on the meeting,
+// switching between scheduled and recurring types. It writes a 400 response
+// and returns false when the rule is invalid.
+func applyRecurrence(c *gin.Context, meeting *models.Meeting, recurrence *models.Recurrence) bool {
+	if recurrence == nil {
+		meeting.Type = zoom.MeetingTypeScheduled
+		meeting.Recurrence = nil
+		return true
+	}
+
+	if err := validateRecurrence(recurrence, meeting.StartTime); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+	meeting.Type = zoom.MeetingTypeRecurringFixed
+	meeting.Recurrence = recurrence
+	return true
+}
+
+// applySettings validates the passcode and settings and applies them to the
+// meeting; a nil password or settings field keeps the current value. It
+// writes a 400 response and returns false when they are invalid.
+func applySettings(c *gin.Context, meeting *models.Meeting, password *string, settings *models.MeetingSettings) bool {
+	if password != nil {
+		if err := validatePassword(*password); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return false
+		}
+		meeting.Password = *password
+	}
+
+	if settings == nil {
+		return true
+	}
+	if err := validateSettings(settings); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+	merged, err := mergeSettings(meeting.Settings, settings)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+	meeting.Settings = merged
+	return true
+}
+
+// newMeetingResponse renders a meeting with its start time in loc
+func newMeetingResponse(meeting models.Meeting, loc *time.Location) MeetingResponse {
+	return MeetingResponse{
+		ID:        meeting.ID,
+		ZoomID:    meeting.ZoomID,
+		Topic:     meeting.Topic,
+		StartTime: meeting.StartTime.In(loc).Format(time.RFC3339),
+		EndTime:   meeting.EndTime().In(loc).Format(time.RFC3339),
+		Duration:  meeting.Duration,
+		Timezone:  meeting.Timezone,
+		JoinURL:   meeting.JoinURL,
+		Status:    meeting.Status,
+		Version:   meeting.Version,
+
+		Type:       meeting.Type,
+		Recurrence: meeting.Recurrence,
+		Password:   meeting.Password,
+		Settings:   meeting.Settings,
+		Agenda:     meeting.Agenda,
+		Invitees:   meeting.Invitees,
+	}
+}
+
+// displayLocation resolves the time zone responses are rendered in: the
+// "timezone" query parameter, else the user's preference. It writes a 400
+// response and returns false when the zone is unknown.
+func displayLocation(c *gin.Context, user models.User) (*time.Location, bool) {
+	loc, err := utils.LoadTimezone(firstNonEmpty(c.Query("timezone"), user.Timezone, utils.DefaultTimezone))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return loc, true
+}
+
+// validDuration writes a 400 response and returns false when duration is out of range
+func validDuration(c *gin.Context, duration int) bool {
+	if duration < 1 || duration > maxMeetingDuration {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "duration must be between 1 and 1440 minutes"})
+		return false
+	}
+	return true
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// respondZoomError writes a Zoom API failure to the response, keeping Zoom's
+// status for client errors such as 404 and reporting everything else as 500
+func respondZoomError(c *gin.Context, err error) {
+	if errors.Is(err, zoom.ErrNotConnected) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Zoom account is not connected"})
+		return
+	}
+
+	var apiErr *zoom.APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode >= 400 && apiErr.StatusCode < 500 && apiErr.StatusCode != http.StatusUnauthorized {
+		c.JSON(apiErr.StatusCode, gin.H{"error": apiErr.Message, "zoom_code": apiErr.Code})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,124 @@
+package controllers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"zoom-meeting-app/utils"
+
+	"gorm.io/gorm"
+)
+
+// Paging limits of GET /meetings
+const (
+	defaultMeetingLimit = 100
+	maxMeetingLimit     = 200
+)
+
+// ListMeta describes the page returned by a paginated list
+type ListMeta struct {
+	Page       int   `json:"page"`
+	Limit      int   `json:"limit"`
+	Total      int64 `json:"total"`
+	TotalPages int   `json:"total_pages"`
+}
+
+// meetingListParams are the filters, sorting and paging of GET /meetings
+type meetingListParams struct {
+	From  *time.Time // Meetings starting at or after From
+	To    *time.Time // Meetings starting before To
+	Query string     // Case-insensitive topic search
+	Desc  bool       // Sort by start time, latest first
+	Page  int
+	Limit int
+}
+
+// parseMeetingListParams reads the from, to, q, sort, page and limit query
+// parameters. Dates without an offset are read in loc.
+func parseMeetingListParams(query func(string) string, loc *time.Location) (meetingListParams, error) {
+	params := meetingListParams{Page: 1, Limit: defaultMeetingLimit}
+
+	if value := query("from"); value != "" {
+		from, err := utils.ParseDateBound(value, loc, false)
+		if err != nil {
+			return params, fmt.Errorf("from: %w", err)
+		}
+		params.From = &from
+	}
+	if value := query("to"); value != "" {
+		to, err := utils.ParseDateBound(value, loc, true)
+		if err != nil {
+			return params, fmt.Errorf("to: %w", err)
+		}
+		params.To = &to
+	}
+	if params.From != nil && params.To != nil && !params.To.After(*params.From) {
+		return params, fmt.Errorf("to must be after from")
+	}
+
+	params.Query = strings.TrimSpace(query("q"))
+
+	switch query("sort") {
+	case "", "start_time":
+	case "-start_time":
+		params.Desc = true
+	default:
+		return params, fmt.Errorf("sort must be start_time or -start_time")
+	}
+
+	if value := query("page"); value != "" {
+		page, err := strconv.Atoi(value)
+		if err != nil || page < 1 {
+			return params, fmt.Errorf("page must be a positive number")
+		}
+		params.Page = page
+	}
+	if value := query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxMeetingLimit {
+			return params, fmt.Errorf("limit must be between 1 and %d", maxMeetingLimit)
+		}
+		params.Limit = limit
+	}
+
+	return params, nil
+}
+
+// filter applies the date range and topic search to a meeting query
+func (p meetingListParams) filter(query *gorm.DB) *gorm.DB {
+	if p.From != nil {
+		query = query.Where("start_time >= ?", *p.From)
+	}
+	if p.To != nil {
+		query = query.Where("start_time < ?", *p.To)
+	}
+	if p.Query != "" {
+		query = query.Where(`LOWER(topic) LIKE ? ESCAPE '\'`, "%"+escapeLike(strings.ToLower(p.Query))+"%")
+	}
+	return query
+}
+
+// paginate applies the sort order and the requested page to a meeting query
+func (p meetingListParams) paginate(query *gorm.DB) *gorm.DB {
+	order := "start_time, id"
+	if p.Desc {
+		order = "start_time DESC, id DESC"
+	}
+	return query.Order(order).Offset((p.Page - 1) * p.Limit).Limit(p.Limit)
+}
+
+// meta describes the requested page of a list with total items
+func (p meetingListParams) meta(total int64) ListMeta {
+	return ListMeta{
+		Page:       p.Page,
+		Limit:      p.Limit,
+		Total:      total,
+		TotalPages: int((total + int64(p.Limit) - 1) / int64(p.Limit)),
+	}
+}
+
+// escapeLike escapes the LIKE wildcards in a search term
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
@@ -0,0 +1,100 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+	"zoom-meeting-app/controllers"
+	"zoom-meeting-app/database"
+	"zoom-meeting-app/models"
+)
+
+func TestGetMeetingsFiltersSortsAndPages(t *testing.T) {
+	api := newTestAPI(t)
+	owner := api.createUser("owner", models.RoleMember)
+	other := api.createUser("other", models.RoleMember)
+
+	at := func(value string) time.Time {
+		start, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return start
+	}
+	for i, m := range []models.Meeting{
+		{Topic: "Daily standup", StartTime: at("2030-01-07T09:00:00Z"), UserID: owner.ID},
+		{Topic: "100% review", StartTime: at("2030-01-08T09:00:00Z"), UserID: owner.ID},
+		{Topic: "snake_case sync", StartTime: at("2030-01-09T09:00:00Z"), UserID: owner.ID},
+		{Topic: "Weekly planning", StartTime: at("2030-01-10T23:30:00Z"), UserID: owner.ID},
+		{Topic: "Other standup", StartTime: at("2030-01-08T09:00:00Z"), UserID: other.ID},
+	} {
+		m.ZoomID = fmt.Sprintf("850000000%02d", i)
+		m.Duration = 30
+		if err := database.DB.Create(&m).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		status     int
+		topics     []string
+		total      int64
+		totalPages int
+	}{
+		{"all in start order", "", http.StatusOK, []string{"Daily standup", "100% review", "snake_case sync", "Weekly planning"}, 4, 1},
+		{"latest first", "sort=-start_time", http.StatusOK, []string{"Weekly planning", "snake_case sync", "100% review", "Daily standup"}, 4, 1},
+		{"whole days", "from=2030-01-08&to=2030-01-09", http.StatusOK, []string{"100% review", "snake_case sync"}, 2, 1},
+		{"from is inclusive", "from=2030-01-08T09:00:00Z", http.StatusOK, []string{"100% review", "snake_case sync", "Weekly planning"}, 3, 1},
+		{"to is exclusive", "to=2030-01-08T09:00:00Z", http.StatusOK, []string{"Daily standup"}, 1, 1},
+		{"days in the requested time zone", "from=2030-01-11&timezone=Asia/Jakarta", http.StatusOK, []string{"Weekly planning"}, 1, 1},
+		{"search ignores case", "q=STANDUP", http.StatusOK, []string{"Daily standup"}, 1, 1},
+		{"percent sign is literal", "q=%25", http.StatusOK, []string{"100% review"}, 1, 1},
+		{"underscore is literal", "q=_", http.StatusOK, []string{"snake_case sync"}, 1, 1},
+		{"search and range", "q=e&from=2030-01-09", http.StatusOK, []string{"snake_case sync", "Weekly planning"}, 2, 1},
+		{"first page", "limit=3", http.StatusOK, []string{"Daily standup", "100% review", "snake_case sync"}, 4, 2},
+		{"last page", "limit=3&page=2", http.StatusOK, []string{"Weekly planning"}, 4, 2},
+		{"page past the end", "limit=3&page=3", http.StatusOK, []string{}, 4, 2},
+		{"no matches", "q=nothing", http.StatusOK, []string{}, 0, 0},
+		{"to before from", "from=2030-01-09&to=2030-01-08", http.StatusBadRequest, nil, 0, 0},
+		{"invalid date", "from=next-week", http.StatusBadRequest, nil, 0, 0},
+		{"unknown sort", "sort=topic", http.StatusBadRequest, nil, 0, 0},
+		{"page zero", "page=0", http.StatusBadRequest, nil, 0, 0},
+		{"limit zero", "limit=0", http.StatusBadRequest, nil, 0, 0},
+		{"limit above the maximum", "limit=201", http.StatusBadRequest, nil, 0, 0},
+		{"limit not a number", "limit=ten", http.StatusBadRequest, nil, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := api.do(owner, http.MethodGet, "/meetings/?"+tt.query, "")
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.status, w.Body)
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+
+			var response struct {
+				Data []controllers.MeetingResponse `json:"data"`
+				Meta controllers.ListMeta          `json:"meta"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatal(err)
+			}
+			topics := []string{}
+			for _, m := range response.Data {
+				topics = append(topics, m.Topic)
+			}
+			if !reflect.DeepEqual(topics, tt.topics) {
+				t.Errorf("topics = %q, want %q", topics, tt.topics)
+			}
+			if response.Meta.Total != tt.total || response.Meta.TotalPages != tt.totalPages {
+				t.Errorf("total %d in %d pages, want %d in %d", response.Meta.Total, response.Meta.TotalPages, tt.total, tt.totalPages)
+			}
+		})
+	}
+}
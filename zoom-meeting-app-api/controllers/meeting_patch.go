@@ -0,0 +1,390 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+	"zoom-meeting-app/database"
+	"zoom-meeting-app/models"
+	"zoom-meeting-app/utils"
+	"zoom-meeting-app/zoom"
+
+	"github.com/gin-gonic/gin"
+)
+
+// patchableMeetingFields lists the members a meeting merge patch may contain
+var patchableMeetingFields = map[string]bool{
+	"topic":      true,
+	"start_time": true,
+	"duration":   true,
+	"timezone":   true,
+	"agenda":     true,
+	"password":   true,
+	"settings":   true,
+	"invitees":   true,
+	"recurrence": true,
+}
+
+// patchableSettingsFields lists the members a settings merge patch may
+// contain, and whether null clears them. The others have no unset value in
+// Zoom, so null is rejected for them.
+var patchableSettingsFields = map[string]bool{
+	"host_video":        false,
+	"participant_video": false,
+	"join_before_host":  false,
+	"mute_upon_entry":   false,
+	"waiting_room":      false,
+	"auto_recording":    false,
+	"alternative_hosts": true,
+	"approval_type":     false,
+}
+
+// meetingPatch collects the changes of a merge patch: the Zoom fields to send
+// and the columns to store
+type meetingPatch struct {
+	fields   map[string]interface{}
+	settings map[string]interface{}
+	columns  []string
+
+	// rescheduled is set when the start time or recurrence of the series
+	// changes, which makes Zoom discard the changes made to single occurrences
+	rescheduled bool
+}
+
+func (p *meetingPatch) set(column string, zoomField string, value interface{}) {
+	p.fields[zoomField] = value
+	p.columns = append(p.columns, column)
+}
+
+// Patch Meeting (PATCH /meetings/:id)
+//
+// The body is a JSON merge patch (RFC 7396): only the members present are
+// changed, and null clears the agenda, passcode, invitees, recurrence or
+// alternative hosts. Nested recurrence and settings objects are merged into
+// the current ones.
+func PatchMeeting(c *gin.Context) {
+	id := c.Param("id")
+
+	currentUser, ok := currentUserFromContext(c)
+	if !ok {
+		return
+	}
+
+	meeting, ok := findAuthorizedMeeting(c, currentUser, id, meetingAccessEdit)
+	if !ok {
+		return
+	}
+
+	// Refuse to overwrite changes the client has not seen
+	if !checkIfMatch(c, meeting) {
+		return
+	}
+
+	var body map[string]json.RawMessage
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "body must be a JSON object"})
+		return
+	}
+
+	patch, err := applyMeetingPatch(&meeting, body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	loc, ok := displayLocation(c, currentUser)
+	if !ok {
+		return
+	}
+
+	if len(patch.columns) > 0 {
+		if err := utils.ZoomClient().PatchMeeting(c.Request.Context(), meeting.UserID, id, patch.fields); err != nil {
+			respondZoomError(c, err)
+			return
+		}
+
+		saved, err := saveMeetingVersion(&meeting, patch.columns...)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save meeting"})
+			return
+		}
+		if !saved {
+			c.JSON(http.StatusPreconditionFailed, gin.H{"error": "Meeting has been modified, reload it and try again"})
+			return
+		}
+
+		if patch.rescheduled {
+			if err := models.ClearOccurrences(database.DB, meeting.ID); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save meeting"})
+				return
+			}
+		}
+	}
+
+	c.Header("ETag", meetingETag(meeting))
+	c.JSON(http.StatusOK, newMeetingResponse(meeting, loc))
+}
+
+// applyMeetingPatch validates a merge patch and applies it to meeting,
+// returning the changes to send to Zoom and store
+func applyMeetingPatch(meeting *models.Meeting, body map[string]json.RawMessage) (*meetingPatch, error) {
+	for name := range body {
+		if !patchableMeetingFields[name] {
+			return nil, fmt.Errorf("unknown field %q", name)
+		}
+	}
+	patch := &meetingPatch{fields: map[string]interface{}{}, settings: map[string]interface{}{}}
+
+	// The time zone comes first, as start times without an offset are read in it
+	if raw, ok := body["timezone"]; ok {
+		var timezone string
+		if err := decodePatchField("timezone", raw, &timezone, false); err != nil {
+			return nil, err
+		}
+		if _, err := utils.LoadTimezone(timezone); err != nil {
+			return nil, err
+		}
+		meeting.Timezone = timezone
+		patch.set("timezone", "timezone", timezone)
+	}
+
+	if raw, ok := body["start_time"]; ok {
+		var value string
+		if err := decodePatchField("start_time", raw, &value, false); err != nil {
+			return nil, err
+		}
+		loc, err := utils.LoadTimezone(firstNonEmpty(meeting.Timezone, utils.DefaultTimezone))
+		if err != nil {
+			return nil, err
+		}
+		startTime, err := utils.ParseStartTime(value, loc)
+		if err != nil {
+			return nil, err
+		}
+		meeting.StartTime = startTime
+		patch.set("start_time", "start_time", startTime.Format(time.RFC3339))
+		patch.rescheduled = true
+	}
+
+	if raw, ok := body["duration"]; ok {
+		var duration int
+		if err := decodePatchField("duration", raw, &duration, false); err != nil {
+			return nil, err
+		}
+		if duration < 1 || duration > maxMeetingDuration {
+			return nil, fmt.Errorf("duration must be between 1 and 1440 minutes")
+		}
+		meeting.Duration = duration
+		patch.set("duration", "duration", duration)
+	}
+
+	if raw, ok := body["topic"]; ok {
+		var topic string
+		if err := decodePatchField("topic", raw, &topic, false); err != nil {
+			return nil, err
+		}
+		if topic == "" {
+			return nil, fmt.Errorf("topic cannot be empty")
+		}
+		meeting.Topic = topic
+		patch.set("topic", "topic", topic)
+	}
+
+	if raw, ok := body["agenda"]; ok {
+		var agenda string
+		if err := decodePatchField("agenda", raw, &agenda, true); err != nil {
+			return nil, err
+		}
+		meeting.Agenda = agenda
+		patch.set("agenda", "agenda", agenda)
+	}
+
+	if raw, ok := body["password"]; ok {
+		var password string
+		if err := decodePatchField("password", raw, &password, true); err != nil {
+			return nil, err
+		}
+		if err := validatePassword(password); err != nil {
+			return nil, err
+		}
+		meeting.Password = password
+		patch.set("password", "password", password)
+	}
+
+	if raw, ok := body["settings"]; ok {
+		update, err := decodeSettingsPatch(raw)
+		if err != nil {
+			return nil, err
+		}
+		if err := validateSettings(update); err != nil {
+			return nil, err
+		}
+		merged, err := mergeSettings(meeting.Settings, update)
+		if err != nil {
+			return nil, err
+		}
+		meeting.Settings = merged
+		patch.columns = append(patch.columns, "settings")
+
+		// Send Zoom only the settings in the patch
+		encoded, err := json.Marshal(toZoomSettings(update))
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(encoded, &patch.settings); err != nil {
+			return nil, err
+		}
+	}
+
+	if raw, ok := body["invitees"]; ok {
+		var emails []string
+		if err := decodePatchField("invitees", raw, &emails, true); err != nil {
+			return nil, err
+		}
+		invitees, err := normalizeEmails("invitees", emails)
+		if err != nil {
+			return nil, err
+		}
+		if len(invitees) == 0 {
+			invitees = nil
+		}
+		meeting.Invitees = invitees
+		patch.columns = append(patch.columns, "invitees")
+
+		zoomInvitees := make([]zoom.Invitee, 0, len(invitees))
+		for _, email := range invitees {
+			zoomInvitees = append(zoomInvitees, zoom.Invitee{Email: email})
+		}
+		patch.settings["meeting_invitees"] = zoomInvitees
+	}
+	if len(patch.settings) > 0 {
+		patch.fields["settings"] = patch.settings
+	}
+
+	if raw, ok := body["recurrence"]; ok {
+		patch.rescheduled = true
+		if isJSONNull(raw) {
+			meeting.Type = zoom.MeetingTypeScheduled
+			meeting.Recurrence = nil
+			patch.set("type", "type", meeting.Type)
+			patch.columns = append(patch.columns, "recurrence")
+		} else {
+			recurrence, err := mergeRecurrence(meeting.Recurrence, raw)
+			if err != nil {
+				return nil, err
+			}
+			if err := validateRecurrence(recurrence, meeting.StartTime); err != nil {
+				return nil, err
+			}
+			meeting.Type = zoom.MeetingTypeRecurringFixed
+			meeting.Recurrence = recurrence
+			patch.set("type", "type", meeting.Type)
+			patch.set("recurrence", "recurrence", toZoomRecurrence(recurrence))
+		}
+	} else if _, moved := body["start_time"]; moved && meeting.Recurrence != nil {
+		// The end date of the series may now fall before its start
+		if err := validateRecurrence(meeting.Recurrence, meeting.StartTime); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Strings(patch.columns)
+	return patch, nil
+}
+
+// decodePatchField decodes a merge patch member into dst. A null member
+// leaves dst at its zero value when nullable, and is rejected otherwise.
+func decodePatchField(name string, raw json.RawMessage, dst interface{}, nullable bool) error {
+	if isJSONNull(raw) {
+		if nullable {
+			return nil
+		}
+		return fmt.Errorf("%s cannot be null", name)
+	}
+	if err := json.Unmarshal(raw, dst); err != nil {
+		return fmt.Errorf("invalid %s", name)
+	}
+	return nil
+}
+
+// decodeSettingsPatch decodes the settings member of a merge patch into the
+// settings to change. Cleared alternative hosts become an empty list, which
+// mergeSettings stores and Zoom receives as no hosts.
+func decodeSettingsPatch(raw json.RawMessage) (*models.MeetingSettings, error) {
+	var members map[string]json.RawMessage
+	if err := decodePatchField("settings", raw, &members, false); err != nil {
+		return nil, err
+	}
+	for name, value := range members {
+		nullable, ok := patchableSettingsFields[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown field %q", "settings."+name)
+		}
+		if isJSONNull(value) && !nullable {
+			return nil, fmt.Errorf("settings.%s cannot be null", name)
+		}
+	}
+
+	var update models.MeetingSettings
+	if err := json.Unmarshal(raw, &update); err != nil {
+		return nil, fmt.Errorf("invalid settings")
+	}
+	if value, ok := members["alternative_hosts"]; ok && isJSONNull(value) {
+		update.AlternativeHosts = []string{}
+	}
+	return &update, nil
+}
+
+// mergeRecurrence applies a merge patch to the current recurrence rule
+func mergeRecurrence(current *models.Recurrence, raw json.RawMessage) (*models.Recurrence, error) {
+	var patch map[string]interface{}
+	if err := json.Unmarshal(raw, &patch); err != nil {
+		return nil, fmt.Errorf("invalid recurrence")
+	}
+
+	target := map[string]interface{}{}
+	if current != nil {
+		encoded, err := json.Marshal(current)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(encoded, &target); err != nil {
+			return nil, err
+		}
+	}
+	mergeJSONObject(target, patch)
+
+	encoded, err := json.Marshal(target)
+	if err != nil {
+		return nil, err
+	}
+	var recurrence models.Recurrence
+	if err := json.Unmarshal(encoded, &recurrence); err != nil {
+		return nil, fmt.Errorf("invalid recurrence")
+	}
+	return &recurrence, nil
+}
+
+// mergeJSONObject applies patch to target with JSON merge-patch semantics
+func mergeJSONObject(target, patch map[string]interface{}) {
+	for key, value := range patch {
+		if value == nil {
+			delete(target, key)
+			continue
+		}
+		if patchObject, ok := value.(map[string]interface{}); ok {
+			if targetObject, ok := target[key].(map[string]interface{}); ok {
+				mergeJSONObject(targetObject, patchObject)
+				continue
+			}
+		}
+		target[key] = value
+	}
+}
+
+func isJSONNull(raw json.RawMessage) bool {
+	return bytes.Equal(bytes.TrimSpace(raw), []byte("null"))
+}
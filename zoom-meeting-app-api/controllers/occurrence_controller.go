@@ -0,0 +1,232 @@
+package controllers
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+	"zoom-meeting-app/database"
+	"zoom-meeting-app/models"
+	"zoom-meeting-app/utils"
+	"zoom-meeting-app/zoom"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Update a single occurrence of a recurring meeting (PUT /meetings/:id/occurrences/:occurrence_id)
+func UpdateOccurrence(c *gin.Context) {
+	id := c.Param("id")
+	occurrenceID := c.Param("occurrence_id")
+
+	// Retrieve the current authenticated user from context
+	currentUser, ok := currentUserFromContext(c)
+	if !ok {
+		return
+	}
+
+	meeting, originalStart, ok := findOccurrenceMeeting(c, currentUser, id, occurrenceID)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		StartTime string `json:"start_time"` // Keeps the occurrence's time when omitted
+		Duration  int    `json:"duration"`   // Minutes, keeps the occurrence's duration when omitted
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if input.StartTime == "" && input.Duration == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "start_time or duration is required"})
+		return
+	}
+	if input.Duration != 0 && !validDuration(c, input.Duration) {
+		return
+	}
+
+	loc, ok := displayLocation(c, currentUser)
+	if !ok {
+		return
+	}
+
+	var occurrence models.MeetingOccurrence
+	if err := database.DB.Where("meeting_id = ? AND occurrence_id = ?", meeting.ID, occurrenceID).FirstOrInit(&occurrence).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load occurrence"})
+		return
+	}
+	if occurrence.Cancelled {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Occurrence has been cancelled"})
+		return
+	}
+
+	req := &zoom.MeetingRequest{Duration: input.Duration}
+	if input.StartTime != "" {
+		meetingLoc, err := utils.LoadTimezone(firstNonEmpty(meeting.Timezone, utils.DefaultTimezone))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		startTime, err := utils.ParseStartTime(input.StartTime, meetingLoc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		req.StartTime = startTime.Format(time.RFC3339)
+		occurrence.StartTime = &startTime
+	}
+	if input.Duration != 0 {
+		occurrence.Duration = input.Duration
+	}
+
+	// Update the occurrence in Zoom
+	if err := utils.ZoomClient().UpdateOccurrence(c.Request.Context(), meeting.UserID, id, occurrenceID, req); err != nil {
+		respondZoomError(c, err)
+		return
+	}
+
+	// Remember the change locally
+	occurrence.MeetingID = meeting.ID
+	occurrence.OccurrenceID = occurrenceID
+	if err := database.DB.Save(&occurrence).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save occurrence"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": newOverriddenOccurrenceResponse(meeting, occurrence, originalStart, loc),
+	})
+}
+
+// Cancel a single occurrence of a recurring meeting (DELETE /meetings/:id/occurrences/:occurrence_id)
+func DeleteOccurrence(c *gin.Context) {
+	id := c.Param("id")
+	occurrenceID := c.Param("occurrence_id")
+
+	currentUser, ok := currentUserFromContext(c)
+	if !ok {
+		return
+	}
+
+	meeting, _, ok := findOccurrenceMeeting(c, currentUser, id, occurrenceID)
+	if !ok {
+		return
+	}
+
+	var occurrence models.MeetingOccurrence
+	if err := database.DB.Where("meeting_id = ? AND occurrence_id = ?", meeting.ID, occurrenceID).FirstOrInit(&occurrence).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load occurrence"})
+		return
+	}
+	if occurrence.Cancelled {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Occurrence has been cancelled"})
+		return
+	}
+
+	// Delete the occurrence from Zoom
+	if err := utils.ZoomClient().DeleteOccurrence(c.Request.Context(), meeting.UserID, id, occurrenceID); err != nil {
+		respondZoomError(c, err)
+		return
+	}
+
+	// Remember the cancellation locally
+	occurrence.MeetingID = meeting.ID
+	occurrence.OccurrenceID = occurrenceID
+	occurrence.Cancelled = true
+	if err := database.DB.Save(&occurrence).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save occurrence"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Occurrence cancelled"})
+}
+
+// findOccurrenceMeeting loads the recurring meeting an occurrence belongs to,
+// checking that user may edit it, and decodes the occurrence's original start
+// time from its ID. It writes an error response and returns false when either
+// is invalid.
+func findOccurrenceMeeting(c *gin.Context, user models.User, id, occurrenceID string) (models.Meeting, time.Time, bool) {
+	meeting, ok := findAuthorizedMeeting(c, user, id, meetingAccessEdit)
+	if !ok {
+		return meeting, time.Time{}, false
+	}
+
+	if meeting.Type != zoom.MeetingTypeRecurringFixed {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Meeting is not recurring"})
+		return meeting, time.Time{}, false
+	}
+
+	originalStart, ok := occurrenceStart(occurrenceID)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Occurrence not found"})
+		return meeting, time.Time{}, false
+	}
+	return meeting, originalStart, true
+}
+
+// occurrenceStart decodes the original start time of an occurrence, which
+// Zoom encodes in its ID as unix milliseconds
+func occurrenceStart(occurrenceID string) (time.Time, bool) {
+	ms, err := strconv.ParseInt(occurrenceID, 10, 64)
+	if err != nil || ms <= 0 {
+		return time.Time{}, false
+	}
+	return time.UnixMilli(ms).UTC(), true
+}
+
+// newOverriddenOccurrenceResponse renders an occurrence with its local changes applied
+func newOverriddenOccurrenceResponse(meeting models.Meeting, occurrence models.MeetingOccurrence, originalStart time.Time, loc *time.Location) OccurrenceResponse {
+	startTime := originalStart
+	if occurrence.StartTime != nil {
+		startTime = *occurrence.StartTime
+	}
+	duration := meeting.Duration
+	if occurrence.Duration != 0 {
+		duration = occurrence.Duration
+	}
+	status := "available"
+	if occurrence.Cancelled {
+		status = "deleted"
+	}
+
+	return newOccurrenceResponses([]zoom.Occurrence{{
+		OccurrenceID: occurrence.OccurrenceID,
+		StartTime:    startTime,
+		Duration:     duration,
+		Status:       status,
+	}}, loc)[0]
+}
+
+// withOccurrenceOverrides adds the locally known cancelled occurrences, which
+// Zoom leaves out of a meeting's occurrences, so calendars can show them as
+// deleted. Moved occurrences are already reported by Zoom at their new time.
+func withOccurrenceOverrides(responses []OccurrenceResponse, meeting models.Meeting, loc *time.Location) []OccurrenceResponse {
+	var overrides []models.MeetingOccurrence
+	database.DB.Where("meeting_id = ? AND cancelled = ?", meeting.ID, true).Find(&overrides)
+
+	known := make(map[string]bool, len(responses))
+	for _, response := range responses {
+		known[response.OccurrenceID] = true
+	}
+
+	added := false
+	for _, override := range overrides {
+		originalStart, ok := occurrenceStart(override.OccurrenceID)
+		if !ok || known[override.OccurrenceID] {
+			continue
+		}
+		responses = append(responses, newOverriddenOccurrenceResponse(meeting, override, originalStart, loc))
+		added = true
+	}
+
+	if added {
+		sort.SliceStable(responses, func(i, j int) bool {
+			a, _ := occurrenceStart(responses[i].OccurrenceID)
+			b, _ := occurrenceStart(responses[j].OccurrenceID)
+			return a.Before(b)
+		})
+	}
+	return responses
+}
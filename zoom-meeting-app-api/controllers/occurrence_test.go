@@ -0,0 +1,49 @@
+package controllers_test
+
+import (
+	"net/http"
+	"testing"
+	"zoom-meeting-app/database"
+	"zoom-meeting-app/models"
+)
+
+func TestOccurrencesCanBeChangedAgainAfterSeriesEdit(t *testing.T) {
+	api := newTestAPI(t)
+	owner := api.createUser("owner", models.RoleMember)
+	series := `{"topic":"Standup","start_time":"2030-01-07T09:00","recurrence":{"type":"daily","end_times":3}}`
+	id := api.createMeeting(owner, series)
+
+	// The second and third day, in unix milliseconds
+	const cancelled, moved = "1894093200000", "1894179600000"
+	changeOccurrences := func() {
+		t.Helper()
+		if w := api.do(owner, http.MethodDelete, "/meetings/"+id+"/occurrences/"+cancelled, ""); w.Code != http.StatusOK {
+			t.Fatalf("cancel occurrence: %d %s", w.Code, w.Body)
+		}
+		if w := api.do(owner, http.MethodPut, "/meetings/"+id+"/occurrences/"+moved, `{"duration":45}`); w.Code != http.StatusOK {
+			t.Fatalf("move occurrence: %d %s", w.Code, w.Body)
+		}
+	}
+
+	changeOccurrences()
+
+	// Editing the series discards the overrides, in Zoom and locally
+	if w := api.doIfMatch(owner, "*", http.MethodPut, "/meetings/"+id, series); w.Code != http.StatusOK {
+		t.Fatalf("update series: %d %s", w.Code, w.Body)
+	}
+	var count int64
+	database.DB.Unscoped().Model(&models.MeetingOccurrence{}).Count(&count)
+	if count != 0 {
+		t.Fatalf("%d overrides left after the series edit, want 0", count)
+	}
+
+	changeOccurrences()
+
+	var overrides []models.MeetingOccurrence
+	database.DB.Order("occurrence_id").Find(&overrides)
+	if len(overrides) != 2 ||
+		overrides[0].OccurrenceID != cancelled || !overrides[0].Cancelled ||
+		overrides[1].OccurrenceID != moved || overrides[1].Duration != 45 {
+		t.Errorf("overrides = %+v, want the second day cancelled and the third 45 minutes long", overrides)
+	}
+}
@@ -0,0 +1,135 @@
+package controllers_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"zoom-meeting-app/database"
+	"zoom-meeting-app/models"
+	"zoom-meeting-app/routes"
+	"zoom-meeting-app/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newRoutedAPI serves the app's real routes, so requests go through the
+// JWT, role and permission middleware
+func newRoutedAPI(t *testing.T) *accountAPI {
+	t.Helper()
+	api := newAccountAPI(t, false)
+	api.cfg.Zoom.CallbackPath = "/auth/callback"
+	api.router = gin.New()
+	routes.SetupRouter(api.router, api.cfg)
+	return api
+}
+
+// userWithRole stores a user with the given role and returns an access token
+// for them
+func (api *accountAPI) userWithRole(role models.Role) (models.User, string) {
+	api.t.Helper()
+	user := models.User{Name: string(role), Email: string(role) + "@example.com", Role: role}
+	if err := database.DB.Create(&user).Error; err != nil {
+		api.t.Fatalf("create user: %v", err)
+	}
+	token, _, err := utils.GenerateToken(user.ID)
+	if err != nil {
+		api.t.Fatal(err)
+	}
+	return user, token
+}
+
+func TestRoutesEnforceRoles(t *testing.T) {
+	api := newRoutedAPI(t)
+	tokens := map[models.Role]string{}
+	for _, role := range models.Roles {
+		_, tokens[role] = api.userWithRole(role)
+	}
+	target, _ := api.userWithRole("")
+
+	// Each request is checked for every role: allowed roles get past the
+	// middleware, the others get 403
+	tests := []struct {
+		method, path string
+		body         interface{}
+		allowed      []models.Role
+	}{
+		{http.MethodGet, "/admin/users", nil, []models.Role{models.RoleAdmin}},
+		{http.MethodPut, fmt.Sprintf("/admin/users/%d/role", target.ID), gin.H{"role": "viewer"}, []models.Role{models.RoleAdmin}},
+		{http.MethodPost, fmt.Sprintf("/admin/users/%d/deactivate", target.ID), nil, []models.Role{models.RoleAdmin}},
+		{http.MethodPost, fmt.Sprintf("/admin/users/%d/reactivate", target.ID), nil, []models.Role{models.RoleAdmin}},
+		{http.MethodGet, "/meetings/", nil, models.Roles},
+		{http.MethodGet, "/templates/", nil, []models.Role{models.RoleAdmin, models.RoleOrganizer, models.RoleMember}},
+		{http.MethodPost, "/templates/", gin.H{"name": "Standup", "topic": "Standup", "duration": 15}, []models.Role{models.RoleAdmin, models.RoleOrganizer, models.RoleMember}},
+	}
+	for _, tt := range tests {
+		allowed := map[models.Role]bool{}
+		for _, role := range tt.allowed {
+			allowed[role] = true
+		}
+		for _, role := range models.Roles {
+			t.Run(fmt.Sprintf("%s %s as %s", tt.method, tt.path, role), func(t *testing.T) {
+				w := api.doAs(tokens[role], tt.method, tt.path, tt.body)
+				if allowed[role] && (w.Code == http.StatusForbidden || w.Code == http.StatusUnauthorized) {
+					t.Errorf("status = %d, want the request allowed: %s", w.Code, w.Body)
+				}
+				if !allowed[role] && w.Code != http.StatusForbidden {
+					t.Errorf("status = %d, want 403: %s", w.Code, w.Body)
+				}
+			})
+		}
+	}
+
+	if w := api.doAs("", http.MethodGet, "/admin/users", nil); w.Code != http.StatusUnauthorized {
+		t.Errorf("admin route without a token: status = %d, want 401", w.Code)
+	}
+}
+
+func TestMemberCannotChangeRoles(t *testing.T) {
+	api := newRoutedAPI(t)
+	member, memberToken := api.userWithRole(models.RoleMember)
+	_, adminToken := api.userWithRole(models.RoleAdmin)
+
+	path := fmt.Sprintf("/admin/users/%d/role", member.ID)
+	if w := api.doAs(memberToken, http.MethodPut, path, gin.H{"role": "admin"}); w.Code != http.StatusForbidden {
+		t.Fatalf("member promotes themselves: status = %d, want 403: %s", w.Code, w.Body)
+	}
+	database.DB.First(&member, member.ID)
+	if member.Role != models.RoleMember {
+		t.Fatalf("role = %s after a refused change", member.Role)
+	}
+
+	if w := api.doAs(adminToken, http.MethodPut, path, gin.H{"role": "admin"}); w.Code != http.StatusOK {
+		t.Fatalf("admin promotes member: status = %d: %s", w.Code, w.Body)
+	}
+
+	// Roles are read on every request, so the existing token gains access
+	if w := api.doAs(memberToken, http.MethodGet, "/admin/users", nil); w.Code != http.StatusOK {
+		t.Errorf("promoted user lists users: status = %d: %s", w.Code, w.Body)
+	}
+}
+
+func TestDeactivationRevokesSessions(t *testing.T) {
+	api := newRoutedAPI(t)
+	_, adminToken := api.userWithRole(models.RoleAdmin)
+	user := api.createUser("alice@example.com", "passw0rd", true)
+	tokens := readSession(t, api.login("alice@example.com", "passw0rd"))
+
+	for _, action := range []string{"deactivate", "reactivate"} {
+		if w := api.doAs(adminToken, http.MethodPost, fmt.Sprintf("/admin/users/%d/%s", user.ID, action), nil); w.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d: %s", action, w.Code, w.Body)
+		}
+	}
+
+	var live int64
+	database.DB.Model(&models.RefreshToken{}).Where("user_id = ? AND revoked_at IS NULL", user.ID).Count(&live)
+	if live != 0 {
+		t.Errorf("%d refresh tokens left unrevoked", live)
+	}
+	if w := api.refresh(tokens.RefreshToken); w.Code != http.StatusUnauthorized {
+		t.Errorf("refresh after reactivation: status = %d, want 401: %s", w.Code, w.Body)
+	}
+	if code := api.me(tokens.AccessToken); code != http.StatusUnauthorized {
+		t.Errorf("me after reactivation: status = %d, want 401", code)
+	}
+	readSession(t, api.login("alice@example.com", "passw0rd"))
+}
@@ -0,0 +1,206 @@
+package controllers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"zoom-meeting-app/models"
+	"zoom-meeting-app/zoom"
+)
+
+// weekdays lists weekday names in Zoom's order, where Sunday is 1
+var weekdays = []string{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"}
+
+// Zoom's limits on recurring meetings
+var maxRepeatInterval = map[string]int{
+	models.RecurrenceDaily:   90,
+	models.RecurrenceWeekly:  12,
+	models.RecurrenceMonthly: 3,
+}
+
+const maxEndTimes = 60
+
+// OccurrenceResponse is a single instance of a recurring meeting
+type OccurrenceResponse struct {
+	OccurrenceID string `json:"occurrence_id"`
+	StartTime    string `json:"start_time"` // Rendered in the requested time zone
+	EndTime      string `json:"end_time"`   // Rendered in the requested time zone
+	Duration     int    `json:"duration"`
+	Status       string `json:"status"`
+}
+
+// validateRecurrence checks a recurrence rule against Zoom's constraints and
+// normalizes it (lowercase names, default repeat interval of 1)
+func validateRecurrence(r *models.Recurrence, startTime time.Time) error {
+	r.Type = strings.ToLower(r.Type)
+	maxInterval, ok := maxRepeatInterval[r.Type]
+	if !ok {
+		return fmt.Errorf("recurrence.type must be one of daily, weekly or monthly")
+	}
+
+	if r.RepeatInterval == 0 {
+		r.RepeatInterval = 1
+	}
+	if r.RepeatInterval < 1 || r.RepeatInterval > maxInterval {
+		return fmt.Errorf("recurrence.repeat_interval must be between 1 and %d for %s meetings", maxInterval, r.Type)
+	}
+
+	switch r.Type {
+	case models.RecurrenceDaily:
+		if len(r.WeeklyDays) > 0 || r.MonthlyDay != 0 || r.MonthlyWeek != 0 || r.MonthlyWeekDay != "" {
+			return fmt.Errorf("daily recurrence does not accept weekly or monthly fields")
+		}
+	case models.RecurrenceWeekly:
+		if len(r.WeeklyDays) == 0 {
+			return fmt.Errorf("recurrence.weekly_days is required for weekly meetings")
+		}
+		for i, day := range r.WeeklyDays {
+			r.WeeklyDays[i] = strings.ToLower(day)
+			if weekdayNumber(r.WeeklyDays[i]) == 0 {
+				return fmt.Errorf("recurrence.weekly_days contains unknown day %q", day)
+			}
+		}
+		if r.MonthlyDay != 0 || r.MonthlyWeek != 0 || r.MonthlyWeekDay != "" {
+			return fmt.Errorf("weekly recurrence does not accept monthly fields")
+		}
+	case models.RecurrenceMonthly:
+		r.MonthlyWeekDay = strings.ToLower(r.MonthlyWeekDay)
+		byDay := r.MonthlyDay != 0
+		byWeek := r.MonthlyWeek != 0 || r.MonthlyWeekDay != ""
+		if byDay == byWeek {
+			return fmt.Errorf("monthly recurrence requires either monthly_day or monthly_week with monthly_week_day")
+		}
+		if byDay && (r.MonthlyDay < 1 || r.MonthlyDay > 31) {
+			return fmt.Errorf("recurrence.monthly_day must be between 1 and 31")
+		}
+		if byWeek {
+			if r.MonthlyWeek != -1 && (r.MonthlyWeek < 1 || r.MonthlyWeek > 4) {
+				return fmt.Errorf("recurrence.monthly_week must be -1 (last) or between 1 and 4")
+			}
+			if weekdayNumber(r.MonthlyWeekDay) == 0 {
+				return fmt.Errorf("recurrence.monthly_week_day must be a day name such as monday")
+			}
+		}
+		if len(r.WeeklyDays) > 0 {
+			return fmt.Errorf("monthly recurrence does not accept weekly_days")
+		}
+	}
+
+	if (r.EndTimes == 0) == (r.EndDateTime == nil) {
+		return fmt.Errorf("recurrence requires exactly one of end_times or end_date_time")
+	}
+	if r.EndDateTime != nil {
+		if !r.EndDateTime.After(startTime) {
+			return fmt.Errorf("recurrence.end_date_time must be after start_time")
+		}
+		endDateTime := r.EndDateTime.UTC().Truncate(time.Second)
+		r.EndDateTime = &endDateTime
+	}
+	if r.EndDateTime == nil && (r.EndTimes < 1 || r.EndTimes > maxEndTimes) {
+		return fmt.Errorf("recurrence.end_times must be between 1 and %d", maxEndTimes)
+	}
+
+	return nil
+}
+
+// toZoomRecurrence converts a validated rule to Zoom's representation
+func toZoomRecurrence(r *models.Recurrence) *zoom.Recurrence {
+	if r == nil {
+		return nil
+	}
+
+	zr := &zoom.Recurrence{
+		RepeatInterval: r.RepeatInterval,
+		MonthlyDay:     r.MonthlyDay,
+		MonthlyWeek:    r.MonthlyWeek,
+		MonthlyWeekDay: weekdayNumber(r.MonthlyWeekDay),
+		EndTimes:       r.EndTimes,
+		EndDateTime:    r.EndDateTime,
+	}
+
+	switch r.Type {
+	case models.RecurrenceDaily:
+		zr.Type = zoom.RecurrenceDaily
+	case models.RecurrenceWeekly:
+		zr.Type = zoom.RecurrenceWeekly
+	case models.RecurrenceMonthly:
+		zr.Type = zoom.RecurrenceMonthly
+	}
+
+	days := make([]string, 0, len(r.WeeklyDays))
+	for _, day := range r.WeeklyDays {
+		days = append(days, strconv.Itoa(weekdayNumber(day)))
+	}
+	zr.WeeklyDays = strings.Join(days, ",")
+
+	return zr
+}
+
+// fromZoomRecurrence converts a rule returned by Zoom to the stored representation
+func fromZoomRecurrence(zr *zoom.Recurrence) *models.Recurrence {
+	if zr == nil {
+		return nil
+	}
+
+	r := &models.Recurrence{
+		RepeatInterval: zr.RepeatInterval,
+		MonthlyDay:     zr.MonthlyDay,
+		MonthlyWeek:    zr.MonthlyWeek,
+		MonthlyWeekDay: weekdayName(zr.MonthlyWeekDay),
+		EndTimes:       zr.EndTimes,
+		EndDateTime:    zr.EndDateTime,
+	}
+
+	switch zr.Type {
+	case zoom.RecurrenceDaily:
+		r.Type = models.RecurrenceDaily
+	case zoom.RecurrenceWeekly:
+		r.Type = models.RecurrenceWeekly
+	case zoom.RecurrenceMonthly:
+		r.Type = models.RecurrenceMonthly
+	}
+
+	for _, day := range strings.Split(zr.WeeklyDays, ",") {
+		if n, err := strconv.Atoi(strings.TrimSpace(day)); err == nil {
+			if name := weekdayName(n); name != "" {
+				r.WeeklyDays = append(r.WeeklyDays, name)
+			}
+		}
+	}
+
+	return r
+}
+
+// newOccurrenceResponses renders occurrences with their times in loc
+func newOccurrenceResponses(occurrences []zoom.Occurrence, loc *time.Location) []OccurrenceResponse {
+	var responses []OccurrenceResponse
+	for _, occurrence := range occurrences {
+		responses = append(responses, OccurrenceResponse{
+			OccurrenceID: occurrence.OccurrenceID,
+			StartTime:    occurrence.StartTime.In(loc).Format(time.RFC3339),
+			EndTime:      occurrence.StartTime.Add(time.Duration(occurrence.Duration) * time.Minute).In(loc).Format(time.RFC3339),
+			Duration:     occurrence.Duration,
+			Status:       occurrence.Status,
+		})
+	}
+	return responses
+}
+
+// weekdayNumber returns Zoom's number for a weekday name, or 0 if unknown
+func weekdayNumber(name string) int {
+	for i, day := range weekdays {
+		if day == name {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// weekdayName returns the name of Zoom's weekday number, or "" if out of range
+func weekdayName(n int) string {
+	if n < 1 || n > len(weekdays) {
+		return ""
+	}
+	return weekdays[n-1]
+}
@@ -0,0 +1,97 @@
+package controllers_test
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"zoom-meeting-app/database"
+	"zoom-meeting-app/models"
+	"zoom-meeting-app/zoom"
+)
+
+func TestCreateMeetingValidatesRecurrence(t *testing.T) {
+	api := newTestAPI(t)
+	owner := api.createUser("owner", models.RoleMember)
+
+	tests := []struct {
+		name       string
+		recurrence string
+		err        string // Part of the 400 error, empty when the rule is valid
+	}{
+		{"daily", `{"type":"daily","end_times":3}`, ""},
+		{"weekly", `{"type":"WEEKLY","repeat_interval":2,"weekly_days":["Monday","wednesday"],"end_times":4}`, ""},
+		{"monthly by day", `{"type":"monthly","monthly_day":15,"end_date_time":"2030-06-01T00:00:00Z"}`, ""},
+		{"monthly on the last friday", `{"type":"monthly","monthly_week":-1,"monthly_week_day":"Friday","end_times":6}`, ""},
+		{"unknown type", `{"type":"yearly","end_times":3}`, "recurrence.type must be one of"},
+		{"interval above Zoom's limit", `{"type":"monthly","repeat_interval":4,"monthly_day":1,"end_times":3}`, "repeat_interval must be between 1 and 3"},
+		{"negative interval", `{"type":"daily","repeat_interval":-1,"end_times":3}`, "repeat_interval must be between 1 and 90"},
+		{"daily with weekdays", `{"type":"daily","weekly_days":["monday"],"end_times":3}`, "daily recurrence does not accept"},
+		{"weekly without days", `{"type":"weekly","end_times":3}`, "weekly_days is required"},
+		{"weekly with unknown day", `{"type":"weekly","weekly_days":["funday"],"end_times":3}`, "contains unknown day"},
+		{"weekly with monthly fields", `{"type":"weekly","weekly_days":["monday"],"monthly_day":3,"end_times":3}`, "weekly recurrence does not accept monthly fields"},
+		{"monthly by day and week", `{"type":"monthly","monthly_day":1,"monthly_week":1,"monthly_week_day":"monday","end_times":3}`, "either monthly_day or monthly_week"},
+		{"monthly without a day", `{"type":"monthly","end_times":3}`, "either monthly_day or monthly_week"},
+		{"monthly day out of range", `{"type":"monthly","monthly_day":32,"end_times":3}`, "monthly_day must be between 1 and 31"},
+		{"monthly week out of range", `{"type":"monthly","monthly_week":5,"monthly_week_day":"monday","end_times":3}`, "monthly_week must be -1"},
+		{"monthly week without a day name", `{"type":"monthly","monthly_week":2,"end_times":3}`, "monthly_week_day must be a day name"},
+		{"no end", `{"type":"daily"}`, "exactly one of end_times or end_date_time"},
+		{"two ends", `{"type":"daily","end_times":3,"end_date_time":"2030-02-01T00:00:00Z"}`, "exactly one of end_times or end_date_time"},
+		{"end before the start", `{"type":"daily","end_date_time":"2030-01-01T00:00:00Z"}`, "end_date_time must be after start_time"},
+		{"too many occurrences", `{"type":"daily","end_times":61}`, "end_times must be between 1 and 60"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := api.do(owner, http.MethodPost, "/meetings/", `{"topic":"Series","start_time":"2030-01-07T09:00","recurrence":`+tt.recurrence+`}`)
+			if tt.err == "" {
+				if w.Code != http.StatusOK {
+					t.Fatalf("status = %d, want 200: %s", w.Code, w.Body)
+				}
+				return
+			}
+			if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), tt.err) {
+				t.Fatalf("status = %d, want 400 with %q: %s", w.Code, tt.err, w.Body)
+			}
+		})
+	}
+
+	var count int64
+	database.DB.Model(&models.Meeting{}).Count(&count)
+	if count != 4 {
+		t.Errorf("%d meetings stored, want only the 4 valid ones", count)
+	}
+}
+
+func TestCreateMeetingSendsRecurrenceToZoom(t *testing.T) {
+	api := newTestAPI(t)
+	owner := api.createUser("owner", models.RoleMember)
+
+	id := api.createMeeting(owner, `{"topic":"Sync","start_time":"2030-01-07T09:00","recurrence":{"type":"Weekly","weekly_days":["Monday","THURSDAY"],"end_times":4}}`)
+
+	zoomMeeting, ok := api.zoom.Meeting(id)
+	if !ok {
+		t.Fatal("meeting not created in Zoom")
+	}
+	want := &zoom.Recurrence{Type: zoom.RecurrenceWeekly, RepeatInterval: 1, WeeklyDays: "2,5", EndTimes: 4}
+	if zoomMeeting.Type != zoom.MeetingTypeRecurringFixed || !reflect.DeepEqual(zoomMeeting.Recurrence, want) {
+		t.Errorf("Zoom meeting type %d, recurrence %+v; want %d, %+v", zoomMeeting.Type, zoomMeeting.Recurrence, zoom.MeetingTypeRecurringFixed, want)
+	}
+
+	// Stored normalized, in the API's names
+	var stored models.Meeting
+	database.DB.First(&stored, "zoom_id = ?", id)
+	if stored.Recurrence == nil || stored.Recurrence.Type != models.RecurrenceWeekly ||
+		!reflect.DeepEqual(stored.Recurrence.WeeklyDays, []string{"monday", "thursday"}) || stored.Recurrence.RepeatInterval != 1 {
+		t.Errorf("stored recurrence = %+v", stored.Recurrence)
+	}
+
+	// Updating without a rule turns it into a single meeting
+	w := api.doIfMatch(owner, "*", http.MethodPut, "/meetings/"+id, `{"topic":"Sync","start_time":"2030-01-07T09:00"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("update: %d %s", w.Code, w.Body)
+	}
+	zoomMeeting, _ = api.zoom.Meeting(id)
+	if zoomMeeting.Type != zoom.MeetingTypeScheduled {
+		t.Errorf("Zoom meeting type = %d after dropping the recurrence, want %d", zoomMeeting.Type, zoom.MeetingTypeScheduled)
+	}
+}
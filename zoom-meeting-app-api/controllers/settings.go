@@ -0,0 +1,151 @@
+package controllers
+
+import (
+	"fmt"
+	"net/mail"
+	"regexp"
+	"strings"
+	"zoom-meeting-app/models"
+	"zoom-meeting-app/zoom"
+)
+
+// Zoom's limits on meeting passcodes
+var passwordPattern = regexp.MustCompile(`^[A-Za-z0-9@_*-]{1,10}$`)
+
+// validatePassword checks a meeting passcode against Zoom's rules. An empty
+// passcode is allowed and leaves the meeting without one.
+func validatePassword(password string) error {
+	if password != "" && !passwordPattern.MatchString(password) {
+		return fmt.Errorf("password must be at most 10 characters of letters, digits, @, -, _ or *")
+	}
+	return nil
+}
+
+// validateSettings checks meeting settings and normalizes them (lowercase
+// recording mode, trimmed and deduplicated alternative hosts)
+func validateSettings(s *models.MeetingSettings) error {
+	s.AutoRecording = strings.ToLower(s.AutoRecording)
+	switch s.AutoRecording {
+	case "", zoom.AutoRecordingLocal, zoom.AutoRecordingCloud, zoom.AutoRecordingNone:
+	default:
+		return fmt.Errorf("settings.auto_recording must be one of local, cloud or none")
+	}
+
+	if s.ApprovalType != nil && (*s.ApprovalType < zoom.ApprovalAutomatic || *s.ApprovalType > zoom.ApprovalNoRegistration) {
+		return fmt.Errorf("settings.approval_type must be 0 (automatic), 1 (manual) or 2 (no registration)")
+	}
+
+	if s.AlternativeHosts != nil {
+		hosts, err := normalizeEmails("settings.alternative_hosts", s.AlternativeHosts)
+		if err != nil {
+			return err
+		}
+		s.AlternativeHosts = hosts
+	}
+
+	return nil
+}
+
+// normalizeEmails trims, lowercases and deduplicates a list of email
+// addresses, reporting the first invalid one against field
+func normalizeEmails(field string, emails []string) ([]string, error) {
+	normalized := make([]string, 0, len(emails))
+	seen := make(map[string]bool)
+	for _, email := range emails {
+		email = strings.ToLower(strings.TrimSpace(email))
+		if address, err := mail.ParseAddress(email); err != nil || address.Address != email {
+			return nil, fmt.Errorf("%s contains invalid email %q", field, email)
+		}
+		if !seen[email] {
+			seen[email] = true
+			normalized = append(normalized, email)
+		}
+	}
+	return normalized, nil
+}
+
+// mergeSettings applies the fields set in update on top of current and
+// checks the combination is allowed
+func mergeSettings(current, update *models.MeetingSettings) (*models.MeetingSettings, error) {
+	merged := models.MeetingSettings{}
+	if current != nil {
+		merged = *current
+	}
+	if update != nil {
+		if update.HostVideo != nil {
+			merged.HostVideo = update.HostVideo
+		}
+		if update.ParticipantVideo != nil {
+			merged.ParticipantVideo = update.ParticipantVideo
+		}
+		if update.JoinBeforeHost != nil {
+			merged.JoinBeforeHost = update.JoinBeforeHost
+		}
+		if update.MuteUponEntry != nil {
+			merged.MuteUponEntry = update.MuteUponEntry
+		}
+		if update.WaitingRoom != nil {
+			merged.WaitingRoom = update.WaitingRoom
+		}
+		if update.AutoRecording != "" {
+			merged.AutoRecording = update.AutoRecording
+		}
+		if update.AlternativeHosts != nil {
+			merged.AlternativeHosts = update.AlternativeHosts
+		}
+		if update.ApprovalType != nil {
+			merged.ApprovalType = update.ApprovalType
+		}
+	}
+
+	// Zoom turns join before host off while the waiting room is on
+	if merged.WaitingRoom != nil && *merged.WaitingRoom && merged.JoinBeforeHost != nil && *merged.JoinBeforeHost {
+		return nil, fmt.Errorf("settings.join_before_host cannot be enabled together with settings.waiting_room")
+	}
+	return &merged, nil
+}
+
+// toZoomSettings converts settings to Zoom's representation
+func toZoomSettings(s *models.MeetingSettings) *zoom.MeetingSettings {
+	if s == nil {
+		return nil
+	}
+
+	zs := &zoom.MeetingSettings{
+		HostVideo:        s.HostVideo,
+		ParticipantVideo: s.ParticipantVideo,
+		JoinBeforeHost:   s.JoinBeforeHost,
+		MuteUponEntry:    s.MuteUponEntry,
+		WaitingRoom:      s.WaitingRoom,
+		AutoRecording:    s.AutoRecording,
+		ApprovalType:     s.ApprovalType,
+	}
+	if s.AlternativeHosts != nil {
+		hosts := strings.Join(s.AlternativeHosts, ";")
+		zs.AlternativeHosts = &hosts
+	}
+	return zs
+}
+
+// fromZoomSettings converts settings returned by Zoom to the stored representation
+func fromZoomSettings(zs *zoom.MeetingSettings) *models.MeetingSettings {
+	if zs == nil {
+		return nil
+	}
+
+	s := &models.MeetingSettings{
+		HostVideo:        zs.HostVideo,
+		ParticipantVideo: zs.ParticipantVideo,
+		JoinBeforeHost:   zs.JoinBeforeHost,
+		MuteUponEntry:    zs.MuteUponEntry,
+		WaitingRoom:      zs.WaitingRoom,
+		AutoRecording:    zs.AutoRecording,
+		ApprovalType:     zs.ApprovalType,
+	}
+	if zs.AlternativeHosts != nil {
+		for _, host := range strings.FieldsFunc(*zs.AlternativeHosts, func(r rune) bool { return r == ';' || r == ',' }) {
+			s.AlternativeHosts = append(s.AlternativeHosts, strings.TrimSpace(host))
+		}
+	}
+	return s
+}
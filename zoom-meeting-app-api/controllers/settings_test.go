@@ -0,0 +1,147 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"zoom-meeting-app/controllers"
+	"zoom-meeting-app/database"
+	"zoom-meeting-app/models"
+	"zoom-meeting-app/zoom"
+)
+
+func TestCreateMeetingValidatesSettings(t *testing.T) {
+	api := newTestAPI(t)
+	owner := api.createUser("owner", models.RoleMember)
+
+	tests := []struct {
+		name   string
+		fields string // Added to a valid meeting body
+		err    string // Part of the 400 error
+	}{
+		{"unknown recording mode", `"settings":{"auto_recording":"tape"}`, "settings.auto_recording must be one of"},
+		{"approval type out of range", `"settings":{"approval_type":3}`, "settings.approval_type must be"},
+		{"invalid alternative host", `"settings":{"alternative_hosts":["bob@example.com","not an email"]}`, "alternative_hosts contains invalid email"},
+		{"join before host in the waiting room", `"settings":{"waiting_room":true,"join_before_host":true}`, "join_before_host cannot be enabled together"},
+		{"password too long", `"password":"12345678901"`, "password must be at most 10 characters"},
+		{"password with spaces", `"password":"a b"`, "password must be at most 10 characters"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := api.do(owner, http.MethodPost, "/meetings/", `{"topic":"Standup","start_time":"2030-01-07T09:00",`+tt.fields+`}`)
+			if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), tt.err) {
+				t.Fatalf("status = %d, want 400 with %q: %s", w.Code, tt.err, w.Body)
+			}
+		})
+	}
+
+	if meetings := api.zoom.Meetings(); len(meetings) != 0 {
+		t.Errorf("%d meetings created in Zoom from invalid requests", len(meetings))
+	}
+}
+
+func TestMeetingSettingsMapToZoom(t *testing.T) {
+	api := newTestAPI(t)
+	owner := api.createUser("owner", models.RoleMember)
+
+	id := api.createMeeting(owner, `{"topic":"Standup","start_time":"2030-01-07T09:00","password":"abc-123","settings":{
+		"host_video":true,"waiting_room":true,"auto_recording":"CLOUD","approval_type":1,
+		"alternative_hosts":[" Bob@Example.com ","bob@example.com","carol@example.com"]}}`)
+
+	zoomMeeting, ok := api.zoom.Meeting(id)
+	if !ok {
+		t.Fatal("meeting not created in Zoom")
+	}
+	zs := zoomMeeting.Settings
+	if zs == nil || !*zs.HostVideo || !*zs.WaitingRoom || zs.AutoRecording != zoom.AutoRecordingCloud || *zs.ApprovalType != zoom.ApprovalManual {
+		t.Errorf("Zoom settings = %+v", zs)
+	}
+	if zs.AlternativeHosts == nil || *zs.AlternativeHosts != "bob@example.com;carol@example.com" {
+		t.Errorf("Zoom alternative hosts = %v, want the normalized addresses joined with ;", zs.AlternativeHosts)
+	}
+	if zoomMeeting.Password != "abc-123" {
+		t.Errorf("Zoom password = %q", zoomMeeting.Password)
+	}
+
+	// A PATCH changes only the settings it names
+	w := api.doIfMatch(owner, "*", http.MethodPatch, "/meetings/"+id, `{"settings":{"mute_upon_entry":true,"auto_recording":"none"}}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("patch: %d %s", w.Code, w.Body)
+	}
+	var response controllers.MeetingResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	s := response.Settings
+	if s == nil || !*s.MuteUponEntry || !*s.WaitingRoom || !*s.HostVideo || s.AutoRecording != zoom.AutoRecordingNone ||
+		!reflect.DeepEqual(s.AlternativeHosts, []string{"bob@example.com", "carol@example.com"}) {
+		t.Errorf("settings after patch = %+v", s)
+	}
+	zoomMeeting, _ = api.zoom.Meeting(id)
+	if !*zoomMeeting.Settings.MuteUponEntry || !*zoomMeeting.Settings.WaitingRoom || zoomMeeting.Settings.AutoRecording != zoom.AutoRecordingNone {
+		t.Errorf("Zoom settings after patch = %+v", zoomMeeting.Settings)
+	}
+
+	// Enabling join before host conflicts with the waiting room kept from before
+	w = api.doIfMatch(owner, "*", http.MethodPatch, "/meetings/"+id, `{"settings":{"join_before_host":true}}`)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("join before host with the stored waiting room: %d %s", w.Code, w.Body)
+	}
+}
+
+func TestPatchClearsAlternativeHosts(t *testing.T) {
+	api := newTestAPI(t)
+	owner := api.createUser("owner", models.RoleMember)
+	id := api.createMeeting(owner, `{"topic":"Standup","start_time":"2030-01-07T09:00","settings":{"waiting_room":true,"alternative_hosts":["bob@example.com"]}}`)
+
+	// Only alternative hosts can be cleared
+	for _, body := range []string{`{"settings":{"waiting_room":null}}`, `{"settings":{"passcode":"abc"}}`} {
+		if w := api.doIfMatch(owner, "*", http.MethodPatch, "/meetings/"+id, body); w.Code != http.StatusBadRequest {
+			t.Errorf("patch %s: status = %d, want 400: %s", body, w.Code, w.Body)
+		}
+	}
+
+	w := api.doIfMatch(owner, "*", http.MethodPatch, "/meetings/"+id, `{"settings":{"alternative_hosts":null}}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("patch: %d %s", w.Code, w.Body)
+	}
+	var stored models.Meeting
+	database.DB.First(&stored, "zoom_id = ?", id)
+	if s := stored.Settings; s == nil || len(s.AlternativeHosts) != 0 || s.WaitingRoom == nil || !*s.WaitingRoom {
+		t.Errorf("stored settings = %+v, want no alternative hosts and the waiting room kept", s)
+	}
+	zoomMeeting, _ := api.zoom.Meeting(id)
+	if hosts := zoomMeeting.Settings.AlternativeHosts; hosts != nil && *hosts != "" {
+		t.Errorf("Zoom alternative hosts = %q, want none", *hosts)
+	}
+}
+
+func TestImportedMeetingSettingsFromZoom(t *testing.T) {
+	api := newTestAPI(t)
+	owner := api.createUser("owner", models.RoleMember)
+	database.DB.Model(&owner).Update("id_zoom", api.zoom.DefaultUser().ID)
+
+	// Zoom may separate alternative hosts with commas
+	id := api.zoom.PutMeeting(api.zoom.DefaultUser().ID, map[string]interface{}{
+		"topic":      "Created in Zoom",
+		"start_time": "2030-01-07T09:00:00Z",
+		"settings": map[string]interface{}{
+			"auto_recording":    zoom.AutoRecordingLocal,
+			"alternative_hosts": "bob@example.com, carol@example.com",
+			"waiting_room":      true,
+		},
+	})
+	if w := api.do(owner, http.MethodGet, "/meetings/"+id, ""); w.Code != http.StatusOK {
+		t.Fatalf("get: %d %s", w.Code, w.Body)
+	}
+
+	var stored models.Meeting
+	database.DB.First(&stored, "zoom_id = ?", id)
+	s := stored.Settings
+	if s == nil || s.AutoRecording != zoom.AutoRecordingLocal || !*s.WaitingRoom ||
+		!reflect.DeepEqual(s.AlternativeHosts, []string{"bob@example.com", "carol@example.com"}) {
+		t.Errorf("stored settings = %+v", s)
+	}
+}
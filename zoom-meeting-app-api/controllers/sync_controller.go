@@ -0,0 +1,44 @@
+package controllers
+
+import (
+	"net/http"
+	"zoom-meeting-app/database"
+	"zoom-meeting-app/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Get the last meeting sync of the current user (GET /meetings/sync)
+func GetSyncStatus(c *gin.Context) {
+	currentUser, ok := currentUserFromContext(c)
+	if !ok {
+		return
+	}
+
+	var status models.MeetingSyncStatus
+	if err := database.DB.Where("user_id = ?", currentUser.ID).First(&status).Error; err != nil {
+		c.JSON(http.StatusOK, gin.H{"data": nil})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": status})
+}
+
+// Sync the current user's meetings with Zoom now (POST /meetings/sync)
+func SyncMeetings(c *gin.Context) {
+	currentUser, ok := currentUserFromContext(c)
+	if !ok {
+		return
+	}
+
+	if meetingSync == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Meeting sync is not available"})
+		return
+	}
+
+	result, err := meetingSync.SyncUser(c.Request.Context(), currentUser.ID)
+	if err != nil {
+		respondZoomError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
@@ -0,0 +1,186 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"zoom-meeting-app/database"
+	"zoom-meeting-app/models"
+	"zoom-meeting-app/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// templateInput is the body of template create and update requests
+type templateInput struct {
+	Name         string                  `json:"name"`
+	TopicPattern string                  `json:"topic_pattern"`
+	Duration     int                     `json:"duration"`
+	Timezone     string                  `json:"timezone"`
+	Agenda       string                  `json:"agenda"`
+	Settings     *models.MeetingSettings `json:"settings"`
+	Invitees     []string                `json:"invitees"`
+}
+
+// List templates (GET /templates)
+func GetTemplates(c *gin.Context) {
+	currentUser, ok := currentUserFromContext(c)
+	if !ok {
+		return
+	}
+
+	var templates []models.MeetingTemplate
+	database.DB.Where("user_id = ?", currentUser.ID).Order("name").Find(&templates)
+	c.JSON(http.StatusOK, gin.H{"data": templates})
+}
+
+// Get template by ID (GET /templates/:id)
+func GetTemplateByID(c *gin.Context) {
+	currentUser, ok := currentUserFromContext(c)
+	if !ok {
+		return
+	}
+
+	template, ok := findTemplate(c, currentUser.ID, c.Param("id"))
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": template})
+}
+
+// Create template (POST /templates)
+func CreateTemplate(c *gin.Context) {
+	currentUser, ok := currentUserFromContext(c)
+	if !ok {
+		return
+	}
+
+	var input templateInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	template := models.MeetingTemplate{UserID: currentUser.ID}
+	if err := input.apply(&template); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := database.DB.Create(&template).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save template"})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": template})
+}
+
+// Update template (PUT /templates/:id)
+func UpdateTemplate(c *gin.Context) {
+	currentUser, ok := currentUserFromContext(c)
+	if !ok {
+		return
+	}
+
+	template, ok := findTemplate(c, currentUser.ID, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	var input templateInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := input.apply(&template); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := database.DB.Save(&template).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save template"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": template})
+}
+
+// Delete template (DELETE /templates/:id)
+func DeleteTemplate(c *gin.Context) {
+	currentUser, ok := currentUserFromContext(c)
+	if !ok {
+		return
+	}
+
+	template, ok := findTemplate(c, currentUser.ID, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	if err := database.DB.Delete(&template).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete template"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Template deleted"})
+}
+
+// apply validates the input and copies it onto the template
+func (input templateInput) apply(template *models.MeetingTemplate) error {
+	if strings.TrimSpace(input.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if input.Duration != 0 && (input.Duration < 1 || input.Duration > maxMeetingDuration) {
+		return fmt.Errorf("duration must be between 1 and 1440 minutes")
+	}
+	if input.Timezone != "" {
+		if _, err := utils.LoadTimezone(input.Timezone); err != nil {
+			return err
+		}
+	}
+
+	var settings *models.MeetingSettings
+	if input.Settings != nil {
+		if err := validateSettings(input.Settings); err != nil {
+			return err
+		}
+		var err error
+		if settings, err = mergeSettings(nil, input.Settings); err != nil {
+			return err
+		}
+	}
+
+	invitees, err := normalizeEmails("invitees", input.Invitees)
+	if err != nil {
+		return err
+	}
+
+	template.Name = strings.TrimSpace(input.Name)
+	template.TopicPattern = input.TopicPattern
+	template.Duration = input.Duration
+	template.Timezone = input.Timezone
+	template.Agenda = input.Agenda
+	template.Settings = settings
+	template.Invitees = invitees
+	return nil
+}
+
+// findTemplate loads a template owned by the user, writing a 404 response
+// when it does not exist
+func findTemplate(c *gin.Context, userID uint, id interface{}) (models.MeetingTemplate, bool) {
+	var template models.MeetingTemplate
+	if err := database.DB.Where("user_id = ?", userID).First(&template, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Template not found"})
+		return template, false
+	}
+	return template, true
+}
+
+// renderTopic fills in the placeholders of a template's topic pattern from
+// the meeting's start, given in the meeting's time zone
+func renderTopic(pattern string, start time.Time) string {
+	return strings.NewReplacer(
+		"{date}", start.Format("2006-01-02"),
+		"{time}", start.Format("15:04"),
+		"{weekday}", start.Weekday().String(),
+	).Replace(pattern)
+}
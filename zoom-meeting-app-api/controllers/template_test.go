@@ -0,0 +1,160 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"zoom-meeting-app/database"
+	"zoom-meeting-app/models"
+	"zoom-meeting-app/zoom"
+)
+
+// createTemplate creates a template owned by owner and returns its ID
+func (api *testAPI) createTemplate(owner models.User, body string) uint {
+	api.t.Helper()
+	w := api.do(owner, http.MethodPost, "/templates/", body)
+	if w.Code != http.StatusCreated {
+		api.t.Fatalf("create template: %d %s", w.Code, w.Body)
+	}
+	var response struct {
+		Data models.MeetingTemplate `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		api.t.Fatalf("decode template: %v", err)
+	}
+	return response.Data.ID
+}
+
+func TestTemplateValidation(t *testing.T) {
+	api := newTestAPI(t)
+	owner := api.createUser("owner", models.RoleMember)
+
+	tests := []struct {
+		name string
+		body string
+		err  string // Part of the 400 error
+	}{
+		{"missing name", `{"name":"  ","duration":30}`, "name is required"},
+		{"duration too long", `{"name":"Long","duration":1441}`, "duration must be between 1 and 1440"},
+		{"unknown time zone", `{"name":"Abroad","timezone":"Mars/Olympus"}`, "Mars/Olympus"},
+		{"invalid settings", `{"name":"Recorded","settings":{"auto_recording":"tape"}}`, "settings.auto_recording"},
+		{"conflicting settings", `{"name":"Open","settings":{"waiting_room":true,"join_before_host":true}}`, "join_before_host cannot be enabled"},
+		{"invalid invitee", `{"name":"Invite","invitees":["nobody"]}`, "invitees contains invalid email"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := api.do(owner, http.MethodPost, "/templates/", tt.body)
+			if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), tt.err) {
+				t.Fatalf("status = %d, want 400 with %q: %s", w.Code, tt.err, w.Body)
+			}
+		})
+	}
+
+	var count int64
+	database.DB.Model(&models.MeetingTemplate{}).Count(&count)
+	if count != 0 {
+		t.Errorf("%d invalid templates stored", count)
+	}
+}
+
+func TestTemplatesBelongToTheirOwner(t *testing.T) {
+	api := newTestAPI(t)
+	owner := api.createUser("owner", models.RoleMember)
+	other := api.createUser("other", models.RoleMember)
+
+	retro := api.createTemplate(owner, `{"name":"Retro","duration":60,"invitees":[" Team@Example.com "]}`)
+	api.createTemplate(owner, `{"name":"Planning"}`)
+	api.createTemplate(other, `{"name":"Other"}`)
+
+	w := api.do(owner, http.MethodGet, "/templates/", "")
+	var list struct {
+		Data []models.MeetingTemplate `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, template := range list.Data {
+		names = append(names, template.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"Planning", "Retro"}) {
+		t.Errorf("templates = %q, want the owner's sorted by name", names)
+	}
+
+	path := fmt.Sprintf("/templates/%d", retro)
+	for _, req := range []struct{ method, body string }{
+		{http.MethodGet, ""},
+		{http.MethodPut, `{"name":"Taken"}`},
+		{http.MethodDelete, ""},
+	} {
+		if w := api.do(other, req.method, path, req.body); w.Code != http.StatusNotFound {
+			t.Errorf("%s as other: status = %d, want 404", req.method, w.Code)
+		}
+	}
+	body := fmt.Sprintf(`{"template_id":%d,"start_time":"2030-01-07T09:00"}`, retro)
+	if w := api.do(other, http.MethodPost, "/meetings/", body); w.Code != http.StatusNotFound {
+		t.Errorf("create meeting from another user's template: status = %d, want 404: %s", w.Code, w.Body)
+	}
+
+	// An update replaces every field
+	if w := api.do(owner, http.MethodPut, path, `{"name":"Sprint retro","duration":45}`); w.Code != http.StatusOK {
+		t.Fatalf("update: %d %s", w.Code, w.Body)
+	}
+	var stored models.MeetingTemplate
+	database.DB.First(&stored, retro)
+	if stored.Name != "Sprint retro" || stored.Duration != 45 || len(stored.Invitees) != 0 {
+		t.Errorf("updated template = %+v", stored)
+	}
+
+	if w := api.do(owner, http.MethodDelete, path, ""); w.Code != http.StatusOK {
+		t.Fatalf("delete: %d %s", w.Code, w.Body)
+	}
+	if w := api.do(owner, http.MethodGet, path, ""); w.Code != http.StatusNotFound {
+		t.Errorf("get after delete: status = %d, want 404", w.Code)
+	}
+}
+
+func TestCreateMeetingFromTemplate(t *testing.T) {
+	api := newTestAPI(t)
+	owner := api.createUser("owner", models.RoleMember)
+	template := api.createTemplate(owner, `{
+		"name":"Standup","topic_pattern":"Standup {weekday} {date} {time}","duration":15,"timezone":"Asia/Jakarta",
+		"agenda":"Yesterday, today, blockers","settings":{"waiting_room":true,"auto_recording":"cloud"},
+		"invitees":["Team@Example.com"]}`)
+
+	// Omitted fields come from the template, the start time is read in its time zone
+	id := api.createMeeting(owner, fmt.Sprintf(`{"template_id":%d,"start_time":"2030-01-07T09:00","settings":{"mute_upon_entry":true}}`, template))
+	var meeting models.Meeting
+	database.DB.First(&meeting, "zoom_id = ?", id)
+	if meeting.Topic != "Standup Monday 2030-01-07 09:00" || meeting.Duration != 15 || meeting.Timezone != "Asia/Jakarta" ||
+		meeting.Agenda != "Yesterday, today, blockers" || !reflect.DeepEqual(meeting.Invitees, []string{"team@example.com"}) {
+		t.Errorf("meeting = topic %q, duration %d, time zone %q, agenda %q, invitees %q",
+			meeting.Topic, meeting.Duration, meeting.Timezone, meeting.Agenda, meeting.Invitees)
+	}
+	if want := time.Date(2030, 1, 7, 2, 0, 0, 0, time.UTC); !meeting.StartTime.Equal(want) {
+		t.Errorf("start = %s, want %s", meeting.StartTime, want)
+	}
+	s := meeting.Settings
+	if s == nil || !*s.WaitingRoom || !*s.MuteUponEntry || s.AutoRecording != zoom.AutoRecordingCloud {
+		t.Errorf("settings = %+v, want the template's with the request's on top", s)
+	}
+	if zoomMeeting, _ := api.zoom.Meeting(id); zoomMeeting.Settings.AutoRecording != zoom.AutoRecordingCloud || !*zoomMeeting.Settings.WaitingRoom {
+		t.Errorf("Zoom settings = %+v", zoomMeeting.Settings)
+	}
+
+	// Fields in the request win over the template
+	id = api.createMeeting(owner, fmt.Sprintf(`{"template_id":%d,"topic":"Demo","duration":50,"timezone":"UTC","start_time":"2030-01-07T09:00","invitees":[]}`, template))
+	meeting = models.Meeting{}
+	database.DB.First(&meeting, "zoom_id = ?", id)
+	if meeting.Topic != "Demo" || meeting.Duration != 50 || meeting.Timezone != "UTC" || len(meeting.Invitees) != 0 {
+		t.Errorf("meeting = topic %q, duration %d, time zone %q, invitees %q; want the request's",
+			meeting.Topic, meeting.Duration, meeting.Timezone, meeting.Invitees)
+	}
+	if want := time.Date(2030, 1, 7, 9, 0, 0, 0, time.UTC); !meeting.StartTime.Equal(want) {
+		t.Errorf("start = %s, want %s", meeting.StartTime, want)
+	}
+}
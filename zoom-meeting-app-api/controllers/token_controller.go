@@ -0,0 +1,240 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+	"zoom-meeting-app/database"
+	"zoom-meeting-app/models"
+	"zoom-meeting-app/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// refreshTokenCookie is only sent to the /auth endpoints
+const (
+	refreshTokenCookie     = "refreshToken"
+	refreshTokenCookiePath = "/auth"
+)
+
+// errInvalidRefreshToken is returned for unknown, expired or revoked refresh tokens
+var errInvalidRefreshToken = errors.New("invalid refresh token")
+
+// tokenPair is the response of login and refresh
+type tokenPair struct {
+	AccessToken  string `json:"accessToken"`
+	RefreshToken string `json:"refreshToken"`
+	ExpiresIn    int    `json:"expiresIn"` // Seconds until the access token expires
+}
+
+// Refresh tokens (POST /auth/refresh)
+//
+// Exchanges a refresh token, from the body or the refreshToken cookie, for a
+// new access token and a new refresh token. Each refresh token works once.
+func RefreshToken(c *gin.Context) {
+	token := refreshTokenFromRequest(c)
+	if token == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Refresh token required"})
+		return
+	}
+
+	var (
+		tokens tokenPair
+		reused bool
+	)
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var stored models.RefreshToken
+		if err := tx.Where("token_hash = ?", utils.HashToken(token)).First(&stored).Error; err != nil {
+			return errInvalidRefreshToken
+		}
+
+		// A used token is presented again: either it or its successor was
+		// stolen, so log out every session of the family
+		if stored.UsedAt != nil {
+			reused = true
+			return revokeTokenFamily(tx, stored.FamilyID)
+		}
+		if stored.RevokedAt != nil || time.Now().After(stored.ExpiresAt) {
+			return errInvalidRefreshToken
+		}
+
+		var user models.User
+		if err := tx.First(&user, stored.UserID).Error; err != nil || !user.Active() {
+			return errInvalidRefreshToken
+		}
+
+		// Claim the token, unless a concurrent request already did
+		result := tx.Model(&models.RefreshToken{}).
+			Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", stored.ID).
+			Update("used_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			reused = true
+			return revokeTokenFamily(tx, stored.FamilyID)
+		}
+
+		var err error
+		tokens, err = issueTokens(tx, user, stored.FamilyID)
+		return err
+	})
+
+	if errors.Is(err, errInvalidRefreshToken) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to refresh token"})
+		return
+	}
+	if reused {
+		clearTokenCookies(c)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Refresh token reuse detected, please log in again"})
+		return
+	}
+
+	setTokenCookies(c, tokens)
+	c.JSON(http.StatusOK, gin.H{"data": tokens})
+}
+
+// Logout (POST /auth/logout)
+//
+// Revokes the access token used for the request and the refresh tokens of
+// the same login, so neither can be used again.
+func Logout(c *gin.Context) {
+	value, _ := c.Get("claims")
+	claims, ok := value.(*utils.Claims)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := revokeAccessToken(tx, claims.ID, claims.ExpiresAt.Time); err != nil {
+			return err
+		}
+
+		// The family of the access token, and of the refresh token if one is
+		// given, as a client may have refreshed without using its new token
+		query := tx.Model(&models.RefreshToken{}).Where("user_id = ? AND access_token_id = ?", claims.UserID, claims.ID)
+		if token := refreshTokenFromRequest(c); token != "" {
+			query = query.Or("user_id = ? AND token_hash = ?", claims.UserID, utils.HashToken(token))
+		}
+		var families []string
+		if err := query.Distinct().Pluck("family_id", &families).Error; err != nil {
+			return err
+		}
+		for _, family := range families {
+			if err := revokeTokenFamily(tx, family); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to log out"})
+		return
+	}
+
+	clearTokenCookies(c)
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out"})
+}
+
+// issueTokens creates an access token and a refresh token for user. The
+// refresh token starts a new family when familyID is empty.
+func issueTokens(tx *gorm.DB, user models.User, familyID string) (tokenPair, error) {
+	accessToken, claims, err := utils.GenerateToken(user.ID)
+	if err != nil {
+		return tokenPair{}, err
+	}
+
+	refreshToken, err := utils.NewRandomToken(32)
+	if err != nil {
+		return tokenPair{}, err
+	}
+	if familyID == "" {
+		if familyID, err = utils.NewRandomToken(16); err != nil {
+			return tokenPair{}, err
+		}
+	}
+
+	stored := models.RefreshToken{
+		UserID:        user.ID,
+		TokenHash:     utils.HashToken(refreshToken),
+		FamilyID:      familyID,
+		AccessTokenID: claims.ID,
+		ExpiresAt:     time.Now().Add(utils.RefreshTokenTTL()),
+	}
+	if err := tx.Create(&stored).Error; err != nil {
+		return tokenPair{}, err
+	}
+
+	return tokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(utils.AccessTokenTTL().Seconds()),
+	}, nil
+}
+
+// revokeTokenFamily revokes every refresh token of a family together with
+// the access tokens issued with them
+func revokeTokenFamily(tx *gorm.DB, familyID string) error {
+	now := time.Now()
+
+	var tokens []models.RefreshToken
+	if err := tx.Where("family_id = ?", familyID).Find(&tokens).Error; err != nil {
+		return err
+	}
+	for _, token := range tokens {
+		expiresAt := token.CreatedAt.Add(utils.AccessTokenTTL())
+		if token.AccessTokenID != "" && expiresAt.After(now) {
+			if err := revokeAccessToken(tx, token.AccessTokenID, expiresAt); err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Model(&models.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", now).Error
+}
+
+// revokeAccessToken rejects the access token with the given jti until it
+// expires, and forgets revocations of tokens that have expired since
+func revokeAccessToken(tx *gorm.DB, jti string, expiresAt time.Time) error {
+	if err := tx.Where("expires_at < ?", time.Now()).Delete(&models.RevokedToken{}).Error; err != nil {
+		return err
+	}
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.RevokedToken{JTI: jti, ExpiresAt: expiresAt}).Error
+}
+
+// refreshTokenFromRequest reads the refresh token from the JSON body or,
+// when absent, from the refresh token cookie
+func refreshTokenFromRequest(c *gin.Context) string {
+	var input struct {
+		RefreshToken string `json:"refreshToken"`
+	}
+	if c.Request.ContentLength != 0 {
+		_ = c.ShouldBindJSON(&input)
+	}
+	if input.RefreshToken != "" {
+		return input.RefreshToken
+	}
+
+	token, _ := c.Cookie(refreshTokenCookie)
+	return token
+}
+
+// setTokenCookies stores both tokens in HTTP-only cookies that expire with them
+func setTokenCookies(c *gin.Context, tokens tokenPair) {
+	c.SetCookie("accessToken", tokens.AccessToken, tokens.ExpiresIn, "/", appConfig.CookieDomain, appConfig.CookieSecure, true)
+	c.SetCookie(refreshTokenCookie, tokens.RefreshToken, int(utils.RefreshTokenTTL().Seconds()), refreshTokenCookiePath, appConfig.CookieDomain, appConfig.CookieSecure, true)
+}
+
+func clearTokenCookies(c *gin.Context) {
+	c.SetCookie("accessToken", "", -1, "/", appConfig.CookieDomain, appConfig.CookieSecure, true)
+	c.SetCookie(refreshTokenCookie, "", -1, refreshTokenCookiePath, appConfig.CookieDomain, appConfig.CookieSecure, true)
+}
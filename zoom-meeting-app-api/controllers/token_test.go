@@ -0,0 +1,200 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"zoom-meeting-app/database"
+	"zoom-meeting-app/models"
+	"zoom-meeting-app/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// session is the token pair returned by login and refresh
+type session struct {
+	AccessToken  string `json:"accessToken"`
+	RefreshToken string `json:"refreshToken"`
+}
+
+// readSession decodes the token pair of a successful login or refresh
+func readSession(t *testing.T, w *httptest.ResponseRecorder) session {
+	t.Helper()
+	var response struct {
+		Data session `json:"data"`
+	}
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body)
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil || response.Data.AccessToken == "" || response.Data.RefreshToken == "" {
+		t.Fatalf("no tokens in %s", w.Body)
+	}
+	return response.Data
+}
+
+func (api *accountAPI) refresh(refreshToken string) *httptest.ResponseRecorder {
+	api.t.Helper()
+	return api.do(http.MethodPost, "/auth/refresh", gin.H{"refreshToken": refreshToken})
+}
+
+// me fetches the current user with accessToken and returns the status
+func (api *accountAPI) me(accessToken string) int {
+	api.t.Helper()
+	return api.doAs(accessToken, http.MethodGet, "/auth/me", nil).Code
+}
+
+func TestRefreshRotatesTokens(t *testing.T) {
+	api := newAccountAPI(t, false)
+	api.createUser("alice@example.com", "passw0rd", true)
+
+	first := readSession(t, api.login("alice@example.com", "passw0rd"))
+	second := readSession(t, api.refresh(first.RefreshToken))
+	if second.RefreshToken == first.RefreshToken || second.AccessToken == first.AccessToken {
+		t.Fatal("refresh returned the tokens it was given")
+	}
+	if code := api.me(second.AccessToken); code != http.StatusOK {
+		t.Errorf("me with the new access token: %d", code)
+	}
+
+	third := readSession(t, api.refresh(second.RefreshToken))
+	if code := api.me(third.AccessToken); code != http.StatusOK {
+		t.Errorf("me after a second refresh: %d", code)
+	}
+
+	var family []models.RefreshToken
+	database.DB.Order("id").Find(&family)
+	if len(family) != 3 || family[0].FamilyID != family[2].FamilyID {
+		t.Fatalf("stored %d refresh tokens, want 3 of one family", len(family))
+	}
+	if family[0].UsedAt == nil || family[1].UsedAt == nil || family[2].UsedAt != nil {
+		t.Error("only the latest refresh token should be unused")
+	}
+}
+
+func TestRefreshTokenReuseRevokesFamily(t *testing.T) {
+	api := newAccountAPI(t, false)
+	api.createUser("alice@example.com", "passw0rd", true)
+
+	stolen := readSession(t, api.login("alice@example.com", "passw0rd"))
+	rotated := readSession(t, api.refresh(stolen.RefreshToken))
+	otherDevice := readSession(t, api.login("alice@example.com", "passw0rd"))
+
+	w := api.refresh(stolen.RefreshToken)
+	if w.Code != http.StatusUnauthorized || !strings.Contains(w.Body.String(), "reuse") {
+		t.Fatalf("reused refresh token: %d %s", w.Code, w.Body)
+	}
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.MaxAge >= 0 {
+			t.Errorf("cookie %s not cleared", cookie.Name)
+		}
+	}
+
+	// Every token of the family is revoked, including the access tokens
+	if w := api.refresh(rotated.RefreshToken); w.Code != http.StatusUnauthorized {
+		t.Errorf("refresh with the rotated token: %d %s", w.Code, w.Body)
+	}
+	for name, token := range map[string]string{"stolen": stolen.AccessToken, "rotated": rotated.AccessToken} {
+		if code := api.me(token); code != http.StatusUnauthorized {
+			t.Errorf("me with the %s access token: %d", name, code)
+		}
+	}
+
+	// Other logins of the user are another family and keep working
+	if code := api.me(otherDevice.AccessToken); code != http.StatusOK {
+		t.Errorf("me from another device: %d", code)
+	}
+	readSession(t, api.refresh(otherDevice.RefreshToken))
+}
+
+func TestLogoutRevokesSession(t *testing.T) {
+	api := newAccountAPI(t, false)
+	api.createUser("alice@example.com", "passw0rd", true)
+
+	s := readSession(t, api.login("alice@example.com", "passw0rd"))
+	other := readSession(t, api.login("alice@example.com", "passw0rd"))
+
+	w := api.doAs(s.AccessToken, http.MethodPost, "/auth/logout", gin.H{"refreshToken": s.RefreshToken})
+	if w.Code != http.StatusOK {
+		t.Fatalf("logout: %d %s", w.Code, w.Body)
+	}
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.MaxAge >= 0 {
+			t.Errorf("cookie %s not cleared", cookie.Name)
+		}
+	}
+
+	if code := api.me(s.AccessToken); code != http.StatusUnauthorized {
+		t.Errorf("me after logout: %d", code)
+	}
+	if w := api.refresh(s.RefreshToken); w.Code != http.StatusUnauthorized {
+		t.Errorf("refresh after logout: %d %s", w.Code, w.Body)
+	}
+	if code := api.me(other.AccessToken); code != http.StatusOK {
+		t.Errorf("me with another login: %d", code)
+	}
+}
+
+func TestRevokedAccessTokenIsRejected(t *testing.T) {
+	api := newAccountAPI(t, false)
+	user := api.createUser("alice@example.com", "passw0rd", true)
+
+	token, claims, err := utils.GenerateToken(user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code := api.me(token); code != http.StatusOK {
+		t.Fatalf("me: %d", code)
+	}
+
+	database.DB.Create(&models.RevokedToken{JTI: claims.ID, ExpiresAt: claims.ExpiresAt.Time})
+	w := api.doAs(token, http.MethodGet, "/auth/me", nil)
+	if w.Code != http.StatusUnauthorized || !strings.Contains(w.Body.String(), "revoked") {
+		t.Errorf("me with a revoked token: %d %s", w.Code, w.Body)
+	}
+
+	// Revoking one jti leaves the user's other tokens alone
+	other, _, err := utils.GenerateToken(user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code := api.me(other); code != http.StatusOK {
+		t.Errorf("me with another token: %d", code)
+	}
+}
+
+func TestTokenCookiesFollowConfig(t *testing.T) {
+	api := newAccountAPI(t, false)
+	api.createUser("alice@example.com", "passw0rd", true)
+
+	tests := []struct {
+		name   string
+		domain string
+		secure bool
+	}{
+		{"development", "", false},
+		{"production", "example.com", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api.cfg.CookieDomain = tt.domain
+			api.cfg.CookieSecure = tt.secure
+
+			w := api.login("alice@example.com", "passw0rd")
+			if w.Code != http.StatusOK {
+				t.Fatalf("login: %d %s", w.Code, w.Body)
+			}
+			cookies := w.Result().Cookies()
+			if len(cookies) != 2 {
+				t.Fatalf("login set %d cookies, want 2", len(cookies))
+			}
+			for _, cookie := range cookies {
+				if cookie.Domain != tt.domain || cookie.Secure != tt.secure || !cookie.HttpOnly {
+					t.Errorf("cookie %s: domain %q, secure %v, http only %v; want %q, %v, true",
+						cookie.Name, cookie.Domain, cookie.Secure, cookie.HttpOnly, tt.domain, tt.secure)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// maxPasswordBytes is the most bcrypt can hash
+const maxPasswordBytes = 72
+
+func init() {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+
+	// Report fields by their JSON names
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	v.RegisterValidation("password", passwordPolicy)
+}
+
+// passwordPolicy is the password policy of the "password" binding tag: at
+// least one letter and one digit, and no more bytes than bcrypt hashes
+func passwordPolicy(fl validator.FieldLevel) bool {
+	password := fl.Field().String()
+	if len(password) > maxPasswordBytes {
+		return false
+	}
+
+	var letter, digit bool
+	for _, r := range password {
+		letter = letter || unicode.IsLetter(r)
+		digit = digit || unicode.IsDigit(r)
+	}
+	return letter && digit
+}
+
+// fieldError describes why one field of a request failed validation
+type fieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// normalizer is implemented by request bodies that clean up their input,
+// e.g. trim it, before it is validated
+type normalizer interface {
+	normalize()
+}
+
+// bindJSON binds the request body into dst and checks its binding tags. It
+// writes a 400 response and returns false when the body is not valid JSON
+// or fails validation, listing every failing field.
+func bindJSON(c *gin.Context, dst interface{}) bool {
+	if c.Request.Body == nil || json.NewDecoder(c.Request.Body).Decode(dst) != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return false
+	}
+	if n, ok := dst.(normalizer); ok {
+		n.normalize()
+	}
+
+	err := binding.Validator.ValidateStruct(dst)
+	if err == nil {
+		return true
+	}
+
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return false
+	}
+
+	fields := make([]fieldError, 0, len(validationErrors))
+	for _, e := range validationErrors {
+		fields = append(fields, fieldError{Field: e.Field(), Message: validationMessage(e)})
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "fields": fields})
+	return false
+}
+
+// validationMessage explains a failed binding tag
+func validationMessage(e validator.FieldError) string {
+	switch e.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "min":
+		return fmt.Sprintf("must be at least %s characters", e.Param())
+	case "max":
+		return fmt.Sprintf("must be at most %s characters", e.Param())
+	case "password":
+		return fmt.Sprintf("must contain a letter and a digit and be at most %d bytes", maxPasswordBytes)
+	}
+	return "is invalid"
+}
@@ -0,0 +1,203 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+	"zoom-meeting-app/database"
+	"zoom-meeting-app/models"
+	"zoom-meeting-app/utils"
+	"zoom-meeting-app/zoom"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxWebhookBody limits the size of webhook requests read into memory
+const maxWebhookBody = 1 << 20
+
+// maxWebhookSaveAttempts bounds how often a meeting changed locally while
+// Zoom was asked for it is fetched again
+const maxWebhookSaveAttempts = 3
+
+// Receive Zoom event notifications (POST /webhooks/zoom)
+func ZoomWebhook(c *gin.Context) {
+	secret := appConfig.Zoom.WebhookSecret
+	if secret == "" {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Zoom webhooks are not configured"})
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxWebhookBody))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+		return
+	}
+
+	if err := zoom.VerifyWebhook(secret, c.Request.Header, body, time.Now()); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	var event zoom.WebhookEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event"})
+		return
+	}
+
+	switch event.Event {
+	case zoom.EventURLValidation:
+		var payload zoom.URLValidationPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil || payload.PlainToken == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid validation payload"})
+			return
+		}
+		c.JSON(http.StatusOK, zoom.NewURLValidationResponse(secret, payload.PlainToken))
+
+	case zoom.EventMeetingCreated, zoom.EventMeetingUpdated, zoom.EventMeetingDeleted, zoom.EventMeetingStarted, zoom.EventMeetingEnded:
+		var payload zoom.MeetingEventPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil || payload.Object.ID == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid meeting payload"})
+			return
+		}
+		if err := handleMeetingEvent(c, event.Event, payload.Object); err != nil {
+			// Zoom retries notifications that are not acknowledged with a 2xx
+			log.Printf("failed to process %s webhook for meeting %s: %v", event.Event, payload.Object.ID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process event"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Event processed"})
+
+	default:
+		c.JSON(http.StatusOK, gin.H{"message": "Event ignored"})
+	}
+}
+
+// handleMeetingEvent applies a meeting event to the meetings table
+func handleMeetingEvent(c *gin.Context, eventType string, object zoom.MeetingEvent) error {
+	id := object.ID.String()
+
+	switch eventType {
+	case zoom.EventMeetingStarted, zoom.EventMeetingEnded:
+		status := models.MeetingStatusStarted
+		if eventType == zoom.EventMeetingEnded {
+			status = models.MeetingStatusEnded
+		}
+		return database.DB.Model(&models.Meeting{}).Where("zoom_id = ?", id).Update("status", status).Error
+
+	case zoom.EventMeetingDeleted:
+		var meeting models.Meeting
+		if err := database.DB.Where("zoom_id = ?", id).First(&meeting).Error; err != nil {
+			return nil // Not tracked locally
+		}
+		if len(object.Occurrences) > 0 {
+			return recordOccurrenceEvents(meeting, object.Occurrences, true)
+		}
+		if err := models.ClearOccurrences(database.DB, meeting.ID); err != nil {
+			return err
+		}
+		return meeting.SoftDelete(database.DB, models.DeletedInZoom)
+	}
+
+	// meeting.created and meeting.updated only carry part of the meeting, so
+	// the full meeting is fetched with the token of its local owner or, for
+	// meetings not stored yet, of the user whose Zoom account hosts it.
+	// Updates often omit host_id, which must then not match users who have
+	// not connected Zoom.
+	var hostID uint
+	var stored models.Meeting
+	if err := database.DB.Where("zoom_id = ?", id).First(&stored).Error; err == nil {
+		hostID = stored.UserID
+	} else if object.HostID != "" {
+		var host models.User
+		if database.DB.Where("id_zoom = ?", object.HostID).First(&host).Error == nil {
+			hostID = host.ID
+		}
+	}
+	if hostID == 0 {
+		return nil // Hosted by someone who has not connected Zoom to the app
+	}
+
+	zoomMeeting, err := utils.ZoomClient().GetMeeting(c.Request.Context(), hostID, id)
+	if zoom.IsNotFound(err) || errors.Is(err, zoom.ErrNotConnected) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	meeting := stored
+	if meeting.ID == 0 {
+		meeting = models.Meeting{ZoomID: id, UserID: hostID}
+		applyZoomMeeting(&meeting, zoomMeeting)
+		_, err := meeting.CreateIfAbsent(database.DB)
+		return err
+	}
+
+	if err := saveZoomMeeting(c, &meeting, zoomMeeting); err != nil {
+		return err
+	}
+	if eventType == zoom.EventMeetingUpdated && len(object.Occurrences) > 0 {
+		return recordOccurrenceEvents(meeting, object.Occurrences, false)
+	}
+	return nil
+}
+
+// saveZoomMeeting stores the meeting as fetched from Zoom. When it was
+// changed locally in the meantime, e.g. by PUT or PATCH, which update Zoom as
+// well, it is loaded and fetched again instead of being overwritten.
+func saveZoomMeeting(c *gin.Context, meeting *models.Meeting, zoomMeeting *zoom.Meeting) error {
+	for attempt := 1; ; attempt++ {
+		if !applyZoomMeeting(meeting, zoomMeeting) {
+			return nil
+		}
+		saved, err := saveMeetingVersion(meeting)
+		if err != nil || saved {
+			return err
+		}
+		if attempt == maxWebhookSaveAttempts {
+			return fmt.Errorf("meeting kept changing after %d attempts", attempt)
+		}
+
+		var current models.Meeting
+		if err := database.DB.First(&current, meeting.ID).Error; err != nil {
+			return err
+		}
+		*meeting = current
+		if zoomMeeting, err = utils.ZoomClient().GetMeeting(c.Request.Context(), meeting.UserID, meeting.ZoomID); err != nil {
+			return err
+		}
+	}
+}
+
+// recordOccurrenceEvents stores occurrences moved or cancelled in Zoom as
+// local overrides
+func recordOccurrenceEvents(meeting models.Meeting, occurrences []zoom.Occurrence, cancelled bool) error {
+	for _, changed := range occurrences {
+		var occurrence models.MeetingOccurrence
+		if err := database.DB.Where("meeting_id = ? AND occurrence_id = ?", meeting.ID, changed.OccurrenceID).FirstOrInit(&occurrence).Error; err != nil {
+			return err
+		}
+
+		occurrence.MeetingID = meeting.ID
+		occurrence.OccurrenceID = changed.OccurrenceID
+		if cancelled {
+			occurrence.Cancelled = true
+		} else {
+			if !changed.StartTime.IsZero() {
+				startTime := changed.StartTime.UTC()
+				occurrence.StartTime = &startTime
+			}
+			if changed.Duration != 0 {
+				occurrence.Duration = changed.Duration
+			}
+		}
+		if err := database.DB.Save(&occurrence).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
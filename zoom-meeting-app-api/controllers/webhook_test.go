@@ -0,0 +1,140 @@
+package controllers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"zoom-meeting-app/database"
+	"zoom-meeting-app/models"
+	"zoom-meeting-app/utils"
+	"zoom-meeting-app/zoom"
+	"zoom-meeting-app/zoom/zoomtest"
+)
+
+// sendWebhook posts a signed Zoom event to the API
+func (api *testAPI) sendWebhook(event string, object map[string]interface{}) *httptest.ResponseRecorder {
+	api.t.Helper()
+	req, err := zoomtest.NewWebhookRequest("/webhooks/zoom", testWebhookSecret, event, map[string]interface{}{"object": object})
+	if err != nil {
+		api.t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	api.router.ServeHTTP(w, req)
+	return w
+}
+
+// useStoredTokens makes the API call Zoom with the tokens stored on each
+// user, as in production, so users who have not connected Zoom cannot
+func (api *testAPI) useStoredTokens() {
+	oauth := utils.ZoomOAuth()
+	api.client = api.zoom.NewClient(zoom.NewUserTokenSource(database.DB, oauth))
+	utils.SetZoom(oauth, api.client)
+}
+
+// connectZoom stores a token of the fake's default Zoom user on user
+func (api *testAPI) connectZoom(user *models.User) {
+	api.t.Helper()
+	zoomUser := api.zoom.DefaultUser()
+	token := api.zoom.IssueToken(zoomUser.ID)
+	user.IdZoom = zoomUser.ID
+	user.ZoomToken = token.AccessToken
+	user.ZoomRefresh = token.RefreshToken
+	user.ZoomExpires = token.Expiry()
+	if err := database.DB.Save(user).Error; err != nil {
+		api.t.Fatal(err)
+	}
+}
+
+func TestWebhookUpdateWithoutHostID(t *testing.T) {
+	api := newTestAPI(t)
+	// Created first so that host_id = "" would match them
+	api.createUser("unconnected", models.RoleAdmin)
+	owner := api.createUser("owner", models.RoleMember)
+	api.connectZoom(&owner)
+	api.useStoredTokens()
+
+	id := api.createMeeting(owner, `{"topic":"Standup","start_time":"2030-01-07T09:00"}`)
+	if err := api.client.PatchMeeting(context.Background(), owner.ID, id, map[string]interface{}{"topic": "Retro"}); err != nil {
+		t.Fatalf("PatchMeeting: %v", err)
+	}
+
+	// Update notifications only carry the changed fields
+	w := api.sendWebhook(zoom.EventMeetingUpdated, map[string]interface{}{"id": json.Number(id), "topic": "Retro"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("webhook: %d %s", w.Code, w.Body)
+	}
+
+	var meeting models.Meeting
+	database.DB.First(&meeting, "zoom_id = ?", id)
+	if meeting.Topic != "Retro" {
+		t.Errorf("topic = %q, want the update from Zoom", meeting.Topic)
+	}
+}
+
+func TestWebhookCancelsOccurrenceAgainAfterSeriesEdit(t *testing.T) {
+	api := newTestAPI(t)
+	owner := api.createUser("owner", models.RoleMember)
+	series := `{"topic":"Standup","start_time":"2030-01-07T09:00","recurrence":{"type":"daily","end_times":3}}`
+	id := api.createMeeting(owner, series)
+
+	// The second day, in unix milliseconds
+	const occurrenceID = "1894093200000"
+	cancel := func() {
+		t.Helper()
+		w := api.sendWebhook(zoom.EventMeetingDeleted, map[string]interface{}{
+			"id":          json.Number(id),
+			"occurrences": []map[string]interface{}{{"occurrence_id": occurrenceID, "start_time": "2030-01-08T09:00:00Z"}},
+		})
+		if w.Code != http.StatusOK {
+			t.Fatalf("webhook: %d %s", w.Code, w.Body)
+		}
+	}
+
+	cancel()
+	if w := api.doIfMatch(owner, "*", http.MethodPut, "/meetings/"+id, series); w.Code != http.StatusOK {
+		t.Fatalf("update series: %d %s", w.Code, w.Body)
+	}
+	cancel()
+
+	var overrides []models.MeetingOccurrence
+	database.DB.Find(&overrides)
+	if len(overrides) != 1 || overrides[0].OccurrenceID != occurrenceID || !overrides[0].Cancelled {
+		t.Errorf("overrides = %+v, want the second day cancelled", overrides)
+	}
+}
+
+func TestWebhookUpdateDoesNotOverwriteConcurrentEdits(t *testing.T) {
+	api := newTestAPI(t)
+	owner := api.createUser("owner", models.RoleMember)
+	id := api.createMeeting(owner, `{"topic":"Standup","start_time":"2030-01-07T09:00"}`)
+	if err := api.client.PatchMeeting(context.Background(), owner.ID, id, map[string]interface{}{"topic": "Retro"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A PATCH through the API updates Zoom and the meeting after the webhook
+	// fetched the meeting
+	api.afterZoomRequest("/v2/meetings/"+id, func() {
+		if err := api.client.PatchMeeting(context.Background(), owner.ID, id, map[string]interface{}{"topic": "Edited"}); err != nil {
+			t.Fatal(err)
+		}
+		var meeting models.Meeting
+		database.DB.First(&meeting, "zoom_id = ?", id)
+		meeting.Topic = "Edited"
+		if err := database.DB.Save(&meeting).Error; err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	w := api.sendWebhook(zoom.EventMeetingUpdated, map[string]interface{}{"id": json.Number(id), "topic": "Retro"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("webhook: %d %s", w.Code, w.Body)
+	}
+
+	var meeting models.Meeting
+	database.DB.First(&meeting, "zoom_id = ?", id)
+	if meeting.Topic != "Edited" {
+		t.Errorf("topic = %q, want the concurrent edit kept", meeting.Topic)
+	}
+}
@@ -3,31 +3,19 @@ package database
 import (
 	"fmt"
 	"log"
-	"os"
 
-	"github.com/joho/godotenv"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"zoom-meeting-app/config"
 	"zoom-meeting-app/models"
 )
 
 var DB *gorm.DB
 
-func ConnectDatabase() {
-	err := godotenv.Load()
-	if err != nil {
-		log.Fatal("Error loading .env file")
-	}
-
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable",
-		os.Getenv("DB_HOST"),
-		os.Getenv("DB_USER"),
-		os.Getenv("DB_PASSWORD"),
-		os.Getenv("DB_NAME"),
-		os.Getenv("DB_PORT"),
-	)
-
-	database, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+func ConnectDatabase(cfg config.DatabaseConfig) {
+	database, err := gorm.Open(postgres.Open(cfg.DSN()), &gorm.Config{
+		TranslateError: true, // Report unique violations as gorm.ErrDuplicatedKey
+	})
 	if err != nil {
 		log.Fatal("Failed to connect to database:", err)
 	}
@@ -37,7 +25,11 @@ func ConnectDatabase() {
 }
 
 func MigrateDatabase() {
-	err := DB.AutoMigrate(&models.User{}, &models.Meeting{}) // Tambahkan model lainnya
+	if err := runDataMigrations(); err != nil {
+		log.Fatal("Failed to migrate existing data:", err)
+	}
+
+	err := DB.AutoMigrate(&models.User{}, &models.Meeting{}, &models.MeetingOccurrence{}, &models.MeetingTemplate{}, &models.MeetingSyncStatus{}, &models.RefreshToken{}, &models.RevokedToken{}, &models.AccountToken{}) // Tambahkan model lainnya
 	if err != nil {
 		log.Fatal("Failed to migrate database:", err)
 	}
@@ -50,7 +42,7 @@ func ResetDatabase() {
 	fmt.Println("Resetting database...")
 
 	// Hapus semua tabel
-	err := DB.Migrator().DropTable(&models.User{}, &models.Meeting{}) // Tambahkan model lain jika ada
+	err := DB.Migrator().DropTable(&models.User{}, &models.Meeting{}, &models.MeetingOccurrence{}, &models.MeetingTemplate{}, &models.MeetingSyncStatus{}, &models.RefreshToken{}, &models.RevokedToken{}, &models.AccountToken{}) // Tambahkan model lain jika ada
 	if err != nil {
 		log.Fatal("Failed to drop tables:", err)
 	}
@@ -64,4 +56,4 @@ func ResetDatabase() {
 	// Jalankan seeder
 	SeedDatabase()
 	fmt.Println("Database seeded!")
-}
\ No newline at end of file
+}
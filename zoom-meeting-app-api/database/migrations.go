@@ -0,0 +1,143 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+	"zoom-meeting-app/models"
+
+	"gorm.io/gorm"
+)
+
+// runDataMigrations converts existing data whose column types changed in a
+// way AutoMigrate cannot handle by itself. Each step is idempotent.
+func runDataMigrations() error {
+	if err := migrateMeetingStartTime(); err != nil {
+		return err
+	}
+	if err := removeDuplicateMeetings(); err != nil {
+		return err
+	}
+	if err := normalizeUserEmails(); err != nil {
+		return err
+	}
+	if err := purgeDeletedOccurrences(); err != nil {
+		return err
+	}
+	return migrateEmailVerification()
+}
+
+// migrateMeetingStartTime converts meetings.start_time from the old text
+// column holding RFC 3339 strings to a timestamp column. Empty or invalid
+// values are not expected; empty strings become NULL.
+//
+// The old code stored every start 7 hours after the time Zoom has, both when
+// creating meetings (Zoom was sent the input minus 7 hours) and when syncing
+// them (Zoom's time plus 7 hours), so the shift is undone here.
+func migrateMeetingStartTime() error {
+	if DB.Dialector.Name() != "postgres" || !DB.Migrator().HasTable(&models.Meeting{}) {
+		return nil
+	}
+
+	columnTypes, err := DB.Migrator().ColumnTypes(&models.Meeting{})
+	if err != nil {
+		return err
+	}
+
+	for _, column := range columnTypes {
+		if column.Name() != "start_time" {
+			continue
+		}
+		typeName := strings.ToLower(column.DatabaseTypeName())
+		if typeName != "text" && !strings.HasPrefix(typeName, "varchar") {
+			return nil
+		}
+
+		fmt.Println("Converting meetings.start_time to timestamptz...")
+		return DB.Exec(`ALTER TABLE meetings ALTER COLUMN start_time TYPE timestamptz USING NULLIF(start_time, '')::timestamptz - interval '7 hours'`).Error
+	}
+	return nil
+}
+
+// migrateEmailVerification adds users.email_verified_at, treating the
+// addresses of users registered before verification existed as verified so
+// they are not locked out when verification is required
+func migrateEmailVerification() error {
+	migrator := DB.Migrator()
+	if !migrator.HasTable(&models.User{}) || migrator.HasColumn(&models.User{}, "EmailVerifiedAt") {
+		return nil
+	}
+
+	fmt.Println("Adding users.email_verified_at...")
+	return DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Migrator().AddColumn(&models.User{}, "EmailVerifiedAt"); err != nil {
+			return err
+		}
+		return tx.Exec("UPDATE users SET email_verified_at = created_at").Error
+	})
+}
+
+// normalizeUserEmails stores users' email addresses in lower case so the
+// case-insensitive unique index on users.email can be created. Accounts whose
+// addresses differ only in case cannot be merged automatically; the
+// migration stops and lists them instead.
+func normalizeUserEmails() error {
+	migrator := DB.Migrator()
+	if !migrator.HasTable(&models.User{}) || migrator.HasIndex(&models.User{}, "idx_users_email_lower") {
+		return nil
+	}
+
+	var duplicates []string
+	if err := DB.Model(&models.User{}).Unscoped().
+		Select("LOWER(TRIM(email))").Group("LOWER(TRIM(email))").Having("COUNT(*) > 1").
+		Pluck("LOWER(TRIM(email))", &duplicates).Error; err != nil {
+		return err
+	}
+	if len(duplicates) > 0 {
+		return fmt.Errorf("users share email addresses that differ only in case, rename or remove all but one user of each: %s",
+			strings.Join(duplicates, ", "))
+	}
+
+	fmt.Println("Normalizing users.email...")
+	return DB.Exec("UPDATE users SET email = LOWER(TRIM(email)) WHERE email <> LOWER(TRIM(email))").Error
+}
+
+// duplicateMeeting matches meetings m for which another row k with the same
+// Zoom ID is kept: a live row over a soft-deleted one, then the most
+// recently updated, then the newest
+const duplicateMeeting = `EXISTS (
+	SELECT 1 FROM meetings k WHERE k.zoom_id = m.zoom_id AND k.id <> m.id AND (
+		(k.deleted_at IS NULL AND m.deleted_at IS NOT NULL) OR
+		((k.deleted_at IS NULL) = (m.deleted_at IS NULL) AND
+			(k.updated_at > m.updated_at OR (k.updated_at = m.updated_at AND k.id > m.id)))))`
+
+// removeDuplicateMeetings deletes meetings imported more than once by
+// concurrent syncs, together with their occurrence overrides, so the unique
+// index on meetings.zoom_id can be created
+func removeDuplicateMeetings() error {
+	migrator := DB.Migrator()
+	if !migrator.HasTable(&models.Meeting{}) || migrator.HasIndex(&models.Meeting{}, "idx_meetings_zoom_id") {
+		return nil
+	}
+
+	fmt.Println("Removing duplicate meetings...")
+	return DB.Transaction(func(tx *gorm.DB) error {
+		if tx.Migrator().HasTable(&models.MeetingOccurrence{}) {
+			if err := tx.Exec("DELETE FROM meeting_occurrences WHERE meeting_id IN (SELECT m.id FROM meetings m WHERE " + duplicateMeeting + ")").Error; err != nil {
+				return err
+			}
+		}
+		return tx.Exec("DELETE FROM meetings WHERE id IN (SELECT m.id FROM meetings m WHERE " + duplicateMeeting + ")").Error
+	})
+}
+
+// purgeDeletedOccurrences removes the occurrence overrides that were soft
+// deleted when their series changed. They blocked the unique index on
+// (meeting_id, occurrence_id), so the same occurrence could not be
+// overridden again.
+func purgeDeletedOccurrences() error {
+	migrator := DB.Migrator()
+	if !migrator.HasTable(&models.MeetingOccurrence{}) {
+		return nil
+	}
+	return DB.Exec("DELETE FROM meeting_occurrences WHERE deleted_at IS NOT NULL").Error
+}
@@ -2,6 +2,7 @@ package database
 
 import (
 	"fmt"
+	"time"
 	"zoom-meeting-app/models"
 
 	"golang.org/x/crypto/bcrypt"
@@ -14,13 +15,43 @@ func SeedDatabase() {
 	DB.Model(&models.User{}).Count(&count)
 	if count == 0 {
 		hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("admin123"), bcrypt.DefaultCost)
+		verifiedAt := time.Now()
 
 		users := []models.User{
-			{Name: "Admin", Email: "admin@local.com", Password: string(hashedPassword)},
-			{Name: "User", Email: "user@local.com", Password: string(hashedPassword)},
+			{Name: "Admin", Email: "admin@local.com", Password: string(hashedPassword), Role: models.RoleAdmin, EmailVerifiedAt: &verifiedAt},
+			{Name: "User", Email: "user@local.com", Password: string(hashedPassword), Role: models.RoleMember, EmailVerifiedAt: &verifiedAt},
 		}
 
 		DB.Create(&users)
 		fmt.Println("User seeding completed!")
 	}
 }
+
+// BootstrapAdmin makes the user with the given email address admin while no
+// admin exists, so deployments upgraded from before roles can reach the
+// admin routes. Nothing happens when email is empty.
+func BootstrapAdmin(email string) error {
+	email = models.NormalizeEmail(email)
+	if email == "" {
+		return nil
+	}
+
+	var admins int64
+	if err := DB.Model(&models.User{}).Where("role = ?", models.RoleAdmin).Count(&admins).Error; err != nil {
+		return err
+	}
+	if admins > 0 {
+		return nil
+	}
+
+	result := DB.Model(&models.User{}).Where("email = ?", email).Update("role", models.RoleAdmin)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		fmt.Printf("No user with BOOTSTRAP_ADMIN_EMAIL %s, register it and restart to make it admin\n", email)
+		return nil
+	}
+	fmt.Printf("Made %s admin\n", email)
+	return nil
+}
@@ -0,0 +1,129 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	netmail "net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// SMTPSender delivers emails through an SMTP server, upgrading the
+// connection with STARTTLS when the server offers it
+type SMTPSender struct {
+	Host     string
+	Port     string
+	Username string // Authenticates with PLAIN when set
+	Password string
+	From     string // A bare address or one with a display name, as in "App <noreply@example.com>"
+}
+
+func (s *SMTPSender) Send(ctx context.Context, msg Message) error {
+	from, to, err := s.addresses(msg)
+	if err != nil {
+		return err
+	}
+	data, err := s.format(from, to, msg)
+	if err != nil {
+		return err
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(s.Host, s.Port))
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, s.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: s.Host}); err != nil {
+			return err
+		}
+	}
+	if s.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.Username, s.Password, s.Host)); err != nil {
+			return err
+		}
+	}
+
+	// The envelope takes bare addresses, display names only go in the headers
+	if err := client.Mail(from.Address); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to.Address); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// addresses parses the sender and the recipient of msg
+func (s *SMTPSender) addresses(msg Message) (from, to *netmail.Address, err error) {
+	for _, value := range []string{s.From, msg.To} {
+		if strings.ContainsAny(value, "\r\n") {
+			return nil, nil, errors.New("mail: header contains a line break")
+		}
+	}
+
+	if from, err = netmail.ParseAddress(s.From); err != nil {
+		return nil, nil, fmt.Errorf("mail: invalid sender %q: %w", s.From, err)
+	}
+	if to, err = netmail.ParseAddress(msg.To); err != nil {
+		return nil, nil, fmt.Errorf("mail: invalid recipient %q: %w", msg.To, err)
+	}
+	return from, to, nil
+}
+
+// format builds the RFC 5322 message, refusing a subject that could inject
+// further headers
+func (s *SMTPSender) format(from, to *netmail.Address, msg Message) ([]byte, error) {
+	if strings.ContainsAny(msg.Subject, "\r\n") {
+		return nil, errors.New("mail: header contains a line break")
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", headerAddress(from))
+	fmt.Fprintf(&buf, "To: %s\r\n", headerAddress(to))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	buf.WriteString("\r\n")
+	body := strings.ReplaceAll(msg.Body, "\r\n", "\n")
+	buf.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	return buf.Bytes(), nil
+}
+
+// headerAddress writes addr for a header, encoding its display name when it
+// has one
+func headerAddress(addr *netmail.Address) string {
+	if addr.Name == "" {
+		return addr.Address
+	}
+	return addr.String()
+}
@@ -0,0 +1,134 @@
+package mail_test
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+	"zoom-meeting-app/mail"
+)
+
+// serveSMTP accepts one connection on ln, speaks just enough SMTP to take a
+// message and returns the commands and the message data it received
+func serveSMTP(t *testing.T, ln net.Listener) <-chan []string {
+	t.Helper()
+	received := make(chan []string, 1)
+	go func() {
+		var lines []string
+		defer func() { received <- lines }()
+
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		reply := func(s string) { conn.Write([]byte(s + "\r\n")) }
+
+		reply("220 localhost ESMTP")
+		inData := false
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			lines = append(lines, line)
+			switch {
+			case inData && line == ".":
+				inData = false
+				reply("250 OK")
+			case inData:
+			case strings.HasPrefix(line, "EHLO"):
+				reply("250 localhost")
+			case line == "DATA":
+				inData = true
+				reply("354 Go ahead")
+			case line == "QUIT":
+				reply("221 Bye")
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+	return received
+}
+
+func TestSMTPSenderDeliversMessage(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	received := serveSMTP(t, ln)
+
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	sender := &mail.SMTPSender{Host: host, Port: port, From: "noreply@example.com"}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err = sender.Send(ctx, mail.Message{To: "user@example.com", Subject: "Reset your password", Body: "Line one\nLine two"})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	session := strings.Join(<-received, "\n")
+	for _, want := range []string{
+		"MAIL FROM:<noreply@example.com>",
+		"RCPT TO:<user@example.com>",
+		"To: user@example.com",
+		"Subject: Reset your password",
+		"Line one\nLine two",
+	} {
+		if !strings.Contains(session, want) {
+			t.Errorf("session lacks %q:\n%s", want, session)
+		}
+	}
+}
+
+func TestSMTPSenderSeparatesDisplayName(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	received := serveSMTP(t, ln)
+
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	sender := &mail.SMTPSender{Host: host, Port: port, From: "Zoom Meeting App <noreply@example.com>"}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := sender.Send(ctx, mail.Message{To: "user@example.com", Subject: "Hi", Body: "Hello"}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	session := strings.Join(<-received, "\n")
+	for _, want := range []string{
+		"MAIL FROM:<noreply@example.com>",
+		`From: "Zoom Meeting App" <noreply@example.com>`,
+	} {
+		if !strings.Contains(session, want) {
+			t.Errorf("session lacks %q:\n%s", want, session)
+		}
+	}
+}
+
+func TestSMTPSenderRejectsInvalidAddress(t *testing.T) {
+	sender := &mail.SMTPSender{Host: "127.0.0.1", Port: "1", From: "noreply@example.com"}
+	err := sender.Send(context.Background(), mail.Message{To: "not an address", Subject: "Hi"})
+	if err == nil || !strings.Contains(err.Error(), "invalid recipient") {
+		t.Fatalf("Send = %v, want an invalid recipient error", err)
+	}
+}
+
+func TestSMTPSenderRejectsHeaderInjection(t *testing.T) {
+	sender := &mail.SMTPSender{Host: "127.0.0.1", Port: "1", From: "noreply@example.com"}
+	err := sender.Send(context.Background(), mail.Message{To: "user@example.com\r\nBcc: victim@example.com", Subject: "Hi"})
+	if err == nil || !strings.Contains(err.Error(), "line break") {
+		t.Fatalf("Send = %v, want a line break error", err)
+	}
+}
@@ -1,9 +1,15 @@
 package main
 
 import (
+	"context"
+	"log"
+	"zoom-meeting-app/config"
+	"zoom-meeting-app/controllers"
 	"zoom-meeting-app/database"
+	"zoom-meeting-app/meetingsync"
 	"zoom-meeting-app/middleware"
 	"zoom-meeting-app/routes"
+	"zoom-meeting-app/utils"
 
 	"github.com/gin-contrib/sessions"
 	"github.com/gin-contrib/sessions/cookie"
@@ -11,13 +17,30 @@ import (
 )
 
 func main() {
-	database.ConnectDatabase()
+	cfg := loadConfig()
+
+	database.ConnectDatabase(cfg.Database)
 	database.MigrateDatabase()
 	database.SeedDatabase()
+	if err := database.BootstrapAdmin(cfg.BootstrapAdminEmail); err != nil {
+		log.Fatal("Failed to bootstrap admin: ", err)
+	}
+
+	if err := utils.InitJWT(cfg.JWT); err != nil {
+		log.Fatal("Failed to load JWT keys: ", err)
+	}
+	utils.InitZoom(cfg)
+	utils.InitMail(cfg.Mail)
+
+	syncService := meetingsync.New(database.DB, utils.ZoomClient(), cfg.MeetingSyncInterval)
+	syncService.Start(context.Background())
+
+	controllers.Init(cfg, syncService)
+
 	r := gin.Default()
 
 	// Set up session middleware (using a cookie store)
-	store := cookie.NewStore([]byte("super-secret-key"))
+	store := cookie.NewStore([]byte(cfg.SessionSecret))
 	r.Use(sessions.Sessions("session", store))
 
 	// Serve static files
@@ -27,11 +50,20 @@ func main() {
 	r.LoadHTMLGlob("templates/*")
 
 	// Apply CORS middleware
-	r.Use(middleware.CORSMiddleware())
+	r.Use(middleware.CORSMiddleware(cfg))
 
 	// Setup routes
-	routes.SetupRouter(r)
+	routes.SetupRouter(r, cfg)
 
 	// Start server
-	r.Run(":8000")
+	r.Run(cfg.ListenAddr)
+}
+
+// loadConfig loads the configuration or exits when it is invalid
+func loadConfig() *config.Config {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+	return cfg
 }
@@ -0,0 +1,237 @@
+// Package meetingsync reconciles the meetings table with Zoom in the
+// background, so listing meetings never has to wait for the Zoom API.
+package meetingsync
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+	"zoom-meeting-app/models"
+	"zoom-meeting-app/zoom"
+
+	"gorm.io/gorm"
+)
+
+// Result summarizes the changes made by a sync
+type Result struct {
+	Added   int `json:"added"`
+	Updated int `json:"updated"`
+	Removed int `json:"removed"`
+}
+
+// Service periodically reconciles the meetings of every Zoom-connected user
+type Service struct {
+	DB       *gorm.DB
+	Client   *zoom.Client
+	Interval time.Duration
+}
+
+// New creates a sync service; it does nothing until Start is called
+func New(db *gorm.DB, client *zoom.Client, interval time.Duration) *Service {
+	return &Service{DB: db, Client: client, Interval: interval}
+}
+
+// Start syncs all users immediately and then every Interval until ctx is
+// cancelled. A zero Interval disables the background sync.
+func (s *Service) Start(ctx context.Context) {
+	if s.Interval <= 0 {
+		log.Println("Meeting sync disabled")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(s.Interval)
+		defer ticker.Stop()
+
+		for {
+			s.SyncAll(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// SyncAll reconciles the meetings of every user who has connected Zoom.
+// Failures are recorded per user and do not stop the other users' sync.
+func (s *Service) SyncAll(ctx context.Context) {
+	var users []models.User
+	if err := s.DB.Where("zoom_refresh <> ? AND deactivated_at IS NULL", "").Find(&users).Error; err != nil {
+		log.Printf("meeting sync: failed to list users: %v", err)
+		return
+	}
+
+	for _, user := range users {
+		if ctx.Err() != nil {
+			return
+		}
+		if _, err := s.SyncUser(ctx, user.ID); err != nil {
+			log.Printf("meeting sync: user %d: %v", user.ID, err)
+		}
+	}
+}
+
+// SyncUser reconciles one user's meetings and records the outcome
+func (s *Service) SyncUser(ctx context.Context, userID uint) (Result, error) {
+	started := time.Now()
+	result, err := s.reconcile(ctx, userID)
+	s.recordStatus(userID, started, result, err)
+	return result, err
+}
+
+// reconcile creates and updates rows from the meetings Zoom lists and soft
+// deletes rows whose meeting no longer exists in Zoom. The result counts the
+// changes made before any error.
+func (s *Service) reconcile(ctx context.Context, userID uint) (Result, error) {
+	var result Result
+
+	// Load the stored meetings before asking Zoom, so a meeting edited while
+	// the list is fetched has a newer version than the one loaded and keeps
+	// the edit instead of being overwritten with the listed data
+	var dbMeetings []models.Meeting
+	if err := s.DB.Where("user_id = ?", userID).Find(&dbMeetings).Error; err != nil {
+		return result, err
+	}
+
+	zoomMeetings, err := s.Client.ListMeetings(ctx, userID, zoom.ListScheduled)
+	if err != nil {
+		return result, err
+	}
+
+	dbMeetingsMap := make(map[string]models.Meeting, len(dbMeetings))
+	for _, meeting := range dbMeetings {
+		dbMeetingsMap[meeting.ZoomID] = meeting
+	}
+
+	listed := make(map[string]bool, len(zoomMeetings))
+	for _, zoomMeeting := range zoomMeetings {
+		zoomID := zoomMeeting.ID.String()
+		listed[zoomID] = true
+
+		meeting, exists := dbMeetingsMap[zoomID]
+		if !exists {
+			meeting = models.Meeting{ZoomID: zoomID, UserID: userID}
+			applyListedMeeting(&meeting, &zoomMeeting)
+			created, err := meeting.CreateIfAbsent(s.DB)
+			if err != nil {
+				return result, err
+			}
+			if created {
+				result.Added++
+			}
+			continue
+		}
+
+		if !applyListedMeeting(&meeting, &zoomMeeting) {
+			continue
+		}
+		updated, err := s.saveListedMeeting(meeting)
+		if err != nil {
+			return result, err
+		}
+		if updated {
+			result.Updated++
+		}
+	}
+
+	now := time.Now()
+	for _, meeting := range dbMeetings {
+		if listed[meeting.ZoomID] || !mayBeListed(meeting, now) {
+			continue
+		}
+
+		// Make sure the meeting is really gone before removing it
+		_, err := s.Client.GetMeeting(ctx, userID, meeting.ZoomID)
+		if err == nil {
+			continue
+		}
+		if !zoom.IsNotFound(err) {
+			return result, err
+		}
+		if err := meeting.SoftDelete(s.DB, models.DeletedInZoom); err != nil {
+			return result, err
+		}
+		result.Removed++
+	}
+
+	return result, nil
+}
+
+// saveListedMeeting writes the listed fields of a stored meeting and bumps
+// its version, unless the meeting changed since it was loaded, e.g. through
+// PUT or PATCH. It reports whether the meeting was written; skipped meetings
+// are reconciled by the next sync.
+func (s *Service) saveListedMeeting(meeting models.Meeting) (bool, error) {
+	result := s.DB.Model(&models.Meeting{}).
+		Where("id = ? AND version = ?", meeting.ID, meeting.Version).
+		Updates(map[string]interface{}{
+			"topic":      meeting.Topic,
+			"type":       meeting.Type,
+			"start_time": meeting.StartTime,
+			"duration":   meeting.Duration,
+			"timezone":   meeting.Timezone,
+			"join_url":   meeting.JoinURL,
+			"version":    meeting.Version + 1,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// applyListedMeeting copies the fields returned by Zoom's meeting list onto a
+// stored meeting and reports whether anything changed. The list leaves out
+// details such as recurrence and settings, which are kept as they are.
+func applyListedMeeting(meeting *models.Meeting, zoomMeeting *zoom.Meeting) bool {
+	// Zoom returns start times in UTC
+	startTime := zoomMeeting.StartTime.UTC()
+
+	updated := meeting.Topic != zoomMeeting.Topic ||
+		meeting.Type != zoomMeeting.Type ||
+		!meeting.StartTime.Equal(startTime) ||
+		meeting.Duration != zoomMeeting.Duration ||
+		meeting.Timezone != zoomMeeting.Timezone ||
+		meeting.JoinURL != zoomMeeting.JoinURL
+
+	meeting.Topic = zoomMeeting.Topic
+	meeting.Type = zoomMeeting.Type
+	meeting.StartTime = startTime
+	meeting.Duration = zoomMeeting.Duration
+	meeting.Timezone = zoomMeeting.Timezone
+	meeting.JoinURL = zoomMeeting.JoinURL
+	return updated
+}
+
+// mayBeListed reports whether Zoom's meeting list should still include the
+// meeting. Zoom drops meetings from the list once they have ended, so those
+// are kept as history rather than treated as deleted.
+func mayBeListed(meeting models.Meeting, now time.Time) bool {
+	return meeting.Type == zoom.MeetingTypeRecurringFixed || meeting.EndTime().After(now)
+}
+
+func (s *Service) recordStatus(userID uint, started time.Time, result Result, syncErr error) {
+	var status models.MeetingSyncStatus
+	s.DB.Where("user_id = ?", userID).FirstOrInit(&status)
+
+	status.UserID = userID
+	status.LastSyncAt = started
+	if syncErr == nil {
+		status.Status = models.SyncStatusOK
+		status.Error = ""
+		status.LastSuccessAt = &started
+		status.Added = result.Added
+		status.Updated = result.Updated
+		status.Removed = result.Removed
+	} else {
+		status.Status = models.SyncStatusFailed
+		status.Error = syncErr.Error()
+		if errors.Is(syncErr, zoom.ErrNotConnected) {
+			status.Error = "Zoom account is not connected"
+		}
+	}
+
+	if err := s.DB.Save(&status).Error; err != nil {
+		log.Printf("meeting sync: failed to record status for user %d: %v", userID, err)
+	}
+}
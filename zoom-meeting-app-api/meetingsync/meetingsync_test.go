@@ -0,0 +1,191 @@
+package meetingsync_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"zoom-meeting-app/database"
+	"zoom-meeting-app/meetingsync"
+	"zoom-meeting-app/models"
+	"zoom-meeting-app/zoom"
+	"zoom-meeting-app/zoom/zoomtest"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// syncTest runs a sync service against an in-memory database and a fake
+// Zoom server. onList, when set, runs while Zoom is asked for the meeting
+// list, as a concurrent request would.
+type syncTest struct {
+	t       *testing.T
+	db      *gorm.DB
+	fake    *zoomtest.Fake
+	client  *zoom.Client
+	service *meetingsync.Service
+	user    models.User
+	onList  func()
+}
+
+func newSyncTest(t *testing.T) *syncTest {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{
+		Logger:         logger.Default.LogMode(logger.Silent),
+		TranslateError: true,
+	})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	database.DB = db
+	database.MigrateDatabase()
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	st := &syncTest{t: t, db: db, fake: zoomtest.NewFake()}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/meetings") && st.onList != nil {
+			st.onList()
+		}
+		st.fake.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	zoomUser := st.fake.DefaultUser()
+	st.client = zoom.NewClient(zoom.StaticToken(st.fake.IssueToken(zoomUser.ID).AccessToken))
+	st.client.BaseURL = srv.URL + "/v2"
+	st.client.HTTPClient = srv.Client()
+	st.service = meetingsync.New(db, st.client, 0)
+
+	st.user = models.User{Name: "Host", Email: "host@example.com", IdZoom: zoomUser.ID, ZoomRefresh: "refresh"}
+	if err := db.Create(&st.user).Error; err != nil {
+		t.Fatal(err)
+	}
+	return st
+}
+
+// putMeeting creates a meeting directly in Zoom and returns its ID
+func (st *syncTest) putMeeting(topic string) string {
+	return st.fake.PutMeeting(st.fake.DefaultUser().ID, map[string]interface{}{
+		"topic":      topic,
+		"type":       zoom.MeetingTypeScheduled,
+		"start_time": "2030-01-07T09:00:00Z",
+		"duration":   30,
+		"timezone":   "UTC",
+	})
+}
+
+func (st *syncTest) sync(want meetingsync.Result) {
+	st.t.Helper()
+	got, err := st.service.SyncUser(context.Background(), st.user.ID)
+	if err != nil {
+		st.t.Fatalf("SyncUser: %v", err)
+	}
+	if got != want {
+		st.t.Fatalf("SyncUser = %+v, want %+v", got, want)
+	}
+}
+
+func (st *syncTest) meeting(zoomID string) models.Meeting {
+	st.t.Helper()
+	var meeting models.Meeting
+	if err := st.db.Unscoped().First(&meeting, "zoom_id = ?", zoomID).Error; err != nil {
+		st.t.Fatalf("meeting %s: %v", zoomID, err)
+	}
+	return meeting
+}
+
+func TestSyncAddsUpdatesAndRemovesMeetings(t *testing.T) {
+	st := newSyncTest(t)
+	kept := st.putMeeting("Standup")
+	removed := st.putMeeting("Retro")
+
+	st.sync(meetingsync.Result{Added: 2})
+
+	if err := st.client.PatchMeeting(context.Background(), st.user.ID, kept, map[string]interface{}{"topic": "Daily"}); err != nil {
+		t.Fatal(err)
+	}
+	st.fake.RemoveMeeting(removed)
+	st.sync(meetingsync.Result{Updated: 1, Removed: 1})
+
+	if m := st.meeting(kept); m.Topic != "Daily" || m.Version != 2 || m.DeletedAt.Valid {
+		t.Errorf("kept meeting = topic %q, version %d, deleted %v; want Daily, 2, live", m.Topic, m.Version, m.DeletedAt.Valid)
+	}
+	if m := st.meeting(removed); !m.DeletedAt.Valid || m.DeletedReason != models.DeletedInZoom {
+		t.Errorf("removed meeting = deleted %v, reason %q; want soft deleted in Zoom", m.DeletedAt.Valid, m.DeletedReason)
+	}
+
+	// Nothing changes on the next run
+	st.sync(meetingsync.Result{})
+	var count int64
+	st.db.Unscoped().Model(&models.Meeting{}).Count(&count)
+	if count != 2 {
+		t.Errorf("%d meetings stored, want 2", count)
+	}
+}
+
+func TestSyncKeepsEndedMeetings(t *testing.T) {
+	st := newSyncTest(t)
+
+	// Zoom no longer lists meetings that have ended
+	ended := models.Meeting{ZoomID: "85000000099", Topic: "Kickoff", StartTime: time.Now().Add(-48 * time.Hour), Duration: 30, UserID: st.user.ID}
+	if err := st.db.Create(&ended).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	st.sync(meetingsync.Result{})
+	if m := st.meeting(ended.ZoomID); m.DeletedAt.Valid {
+		t.Error("ended meeting was removed")
+	}
+}
+
+func TestSyncDoesNotOverwriteConcurrentEdits(t *testing.T) {
+	st := newSyncTest(t)
+	id := st.putMeeting("Standup")
+	st.sync(meetingsync.Result{Added: 1})
+
+	if err := st.client.PatchMeeting(context.Background(), st.user.ID, id, map[string]interface{}{"topic": "Renamed in Zoom"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A PATCH through the API saves its change while the sync waits for Zoom
+	st.onList = func() {
+		st.db.Model(&models.Meeting{}).Where("zoom_id = ?", id).Updates(map[string]interface{}{"topic": "Edited", "version": 2})
+	}
+	st.sync(meetingsync.Result{})
+	if m := st.meeting(id); m.Topic != "Edited" || m.Version != 2 {
+		t.Errorf("meeting = topic %q, version %d; want the edit kept", m.Topic, m.Version)
+	}
+
+	// The next sync reconciles it with Zoom
+	st.onList = nil
+	st.sync(meetingsync.Result{Updated: 1})
+	if m := st.meeting(id); m.Topic != "Renamed in Zoom" || m.Version != 3 {
+		t.Errorf("meeting = topic %q, version %d; want Zoom's topic and version 3", m.Topic, m.Version)
+	}
+}
+
+func TestSyncImportsMeetingsOnce(t *testing.T) {
+	st := newSyncTest(t)
+	id := st.putMeeting("Standup")
+
+	// A webhook imports the meeting while the sync waits for Zoom
+	st.onList = func() {
+		st.db.Create(&models.Meeting{ZoomID: id, Topic: "Standup", UserID: st.user.ID})
+	}
+	st.sync(meetingsync.Result{})
+
+	var count int64
+	st.db.Model(&models.Meeting{}).Where("zoom_id = ?", id).Count(&count)
+	if count != 1 {
+		t.Errorf("meeting stored %d times, want once", count)
+	}
+}
@@ -50,6 +50,11 @@ func AuthMiddleware() gin.HandlerFunc {
 			c.Abort()
 			return
 		}
+		if models.TokenRevoked(database.DB, claims.ID) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token has been revoked"})
+			c.Abort()
+			return
+		}
 
 		// Fetch user from database
 		var user models.User
@@ -58,9 +63,15 @@ func AuthMiddleware() gin.HandlerFunc {
 			c.Abort()
 			return
 		}
+		if !user.Active() {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Account is deactivated"})
+			c.Abort()
+			return
+		}
 
 		// Set user in context
 		c.Set("user", user)
+		c.Set("claims", claims)
 		c.Next()
 	}
 }
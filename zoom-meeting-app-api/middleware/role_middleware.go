@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+	"zoom-meeting-app/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireRole allows the request only when the user set by AuthMiddleware
+// has one of the given roles
+func RequireRole(roles ...models.Role) gin.HandlerFunc {
+	return requireUser(func(user models.User) bool {
+		for _, role := range roles {
+			if user.Role == role {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// RequirePermission allows the request only when the role of the user set
+// by AuthMiddleware grants permission
+func RequirePermission(permission models.Permission) gin.HandlerFunc {
+	return requireUser(func(user models.User) bool {
+		return user.Can(permission)
+	})
+}
+
+func requireUser(allowed func(models.User) bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, exists := c.Get("user")
+		user, ok := value.(models.User)
+		if !exists || !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			c.Abort()
+			return
+		}
+
+		if !allowed(user) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -3,6 +3,6 @@ package main
 import "zoom-meeting-app/database"
 
 func RunMigration() {
-	database.ConnectDatabase()
+	database.ConnectDatabase(loadConfig().Database)
 	database.MigrateDatabase()
-}
\ No newline at end of file
+}
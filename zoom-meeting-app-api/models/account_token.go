@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// AccountTokenPurpose is what an account token can be used for
+type AccountTokenPurpose string
+
+const (
+	AccountTokenPasswordReset     AccountTokenPurpose = "password_reset"
+	AccountTokenEmailVerification AccountTokenPurpose = "email_verification"
+)
+
+// AccountToken is a single-use token emailed to a user to reset their
+// password or verify their email address. Only a SHA-256 hash of the token
+// is stored. Issuing a token replaces the user's unused tokens of the same
+// purpose.
+type AccountToken struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	UserID    uint                `gorm:"not null;index"`
+	Purpose   AccountTokenPurpose `gorm:"type:varchar(30);not null"`
+	TokenHash string              `gorm:"not null;uniqueIndex"`
+	ExpiresAt time.Time           `gorm:"not null"`
+	UsedAt    *time.Time          // Set when the token is used
+}
@@ -1,13 +1,82 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Meeting statuses, kept up to date by Zoom webhooks
+const (
+	MeetingStatusWaiting = "waiting"
+	MeetingStatusStarted = "started"
+	MeetingStatusEnded   = "ended"
+)
+
+// Reasons a meeting was deleted
+const (
+	DeletedByUser = "deleted_by_user" // Deleted through this app
+	DeletedInZoom = "deleted_in_zoom" // Deleted in Zoom, found by a webhook or the sync
+)
+
+// DefaultMeetingDuration is used when a meeting is created without a duration
+const DefaultMeetingDuration = 30
 
 type Meeting struct {
 	gorm.Model
-	ZoomID    string `json:"zoom_id"`
-	Topic     string `json:"topic"`
-	StartTime string `json:"start_time"`
-	JoinURL   string `json:"join_url"`
-	UserID    uint   `json:"user_id"`
-	User      User   `json:"user" gorm:"foreignKey:UserID"`
+	ZoomID     string           `json:"zoom_id" gorm:"uniqueIndex"`
+	Topic      string           `json:"topic"`
+	Type       int              `json:"type" gorm:"default:2"` // Zoom meeting type: 2 scheduled, 8 recurring
+	Recurrence *Recurrence      `json:"recurrence" gorm:"serializer:json"`
+	Settings   *MeetingSettings `json:"settings" gorm:"serializer:json"`
+	Password   string           `json:"password"` // Meeting passcode
+	Agenda     string           `json:"agenda"`
+	Invitees   []string         `json:"invitees" gorm:"serializer:json"`                            // Email addresses Zoom sends the invitation to
+	StartTime  time.Time        `json:"start_time" gorm:"index:idx_meetings_user_start,priority:2"` // Stored in UTC
+	Duration   int              `json:"duration" gorm:"default:30"`                                 // Minutes
+	Timezone   string           `json:"timezone"`                                                   // IANA time zone the meeting is scheduled in
+	JoinURL    string           `json:"join_url"`
+	Status     string           `json:"status" gorm:"default:waiting"`
+	// Version is bumped on every update and exposed as the ETag
+	Version uint `json:"version" gorm:"not null;default:1"`
+	// DeletedReason tells why a soft-deleted meeting was removed
+	DeletedReason string `json:"deleted_reason,omitempty"`
+	UserID        uint   `json:"user_id" gorm:"index:idx_meetings_user_start,priority:1"`
+	User          User   `json:"user" gorm:"foreignKey:UserID"`
+}
+
+// BeforeUpdate bumps the version of a loaded meeting on every update, so
+// clients holding an older ETag can be told the meeting has changed
+func (m *Meeting) BeforeUpdate(tx *gorm.DB) error {
+	if m.ID != 0 {
+		tx.Statement.SetColumn("Version", m.Version+1)
+	}
+	return nil
+}
+
+// CreateIfAbsent inserts the meeting unless one with the same Zoom ID is
+// already stored, e.g. imported concurrently by the sync or a webhook, and
+// reports whether it was inserted
+func (m *Meeting) CreateIfAbsent(db *gorm.DB) (bool, error) {
+	result := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "zoom_id"}},
+		DoNothing: true,
+	}).Create(m)
+	return result.RowsAffected > 0, result.Error
+}
+
+// SoftDelete marks the meeting as deleted for the given reason
+func (m *Meeting) SoftDelete(db *gorm.DB, reason string) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(m).Update("deleted_reason", reason).Error; err != nil {
+			return err
+		}
+		return tx.Delete(m).Error
+	})
+}
+
+// EndTime returns the scheduled end of the meeting
+func (m Meeting) EndTime() time.Time {
+	return m.StartTime.Add(time.Duration(m.Duration) * time.Minute)
 }
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MeetingOccurrence records a change made to a single occurrence of a
+// recurring meeting, so moved and cancelled instances are known locally
+type MeetingOccurrence struct {
+	gorm.Model
+	MeetingID    uint       `json:"meeting_id" gorm:"uniqueIndex:idx_meeting_occurrence"`
+	OccurrenceID string     `json:"occurrence_id" gorm:"uniqueIndex:idx_meeting_occurrence"` // Zoom occurrence ID, the original start in unix milliseconds
+	StartTime    *time.Time `json:"start_time"`                                              // New start in UTC, nil when not moved
+	Duration     int        `json:"duration"`                                                // New duration in minutes, 0 when unchanged
+	Cancelled    bool       `json:"cancelled"`
+}
+
+// ClearOccurrences removes the overrides of a meeting's occurrences, which
+// Zoom discards when the whole series changes. They are deleted for good,
+// as a soft-deleted row would still hold its place in the unique index.
+func ClearOccurrences(db *gorm.DB, meetingID uint) error {
+	return db.Unscoped().Where("meeting_id = ?", meetingID).Delete(&MeetingOccurrence{}).Error
+}
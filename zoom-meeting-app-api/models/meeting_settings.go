@@ -0,0 +1,16 @@
+package models
+
+// MeetingSettings are the Zoom options of a meeting, stored as JSON on the
+// meeting. In requests, omitted fields keep their current value.
+// AutoRecording is local, cloud or none and ApprovalType is 0 (automatic),
+// 1 (manual) or 2 (no registration required), as in Zoom's API.
+type MeetingSettings struct {
+	HostVideo        *bool    `json:"host_video,omitempty"`
+	ParticipantVideo *bool    `json:"participant_video,omitempty"`
+	JoinBeforeHost   *bool    `json:"join_before_host,omitempty"`
+	MuteUponEntry    *bool    `json:"mute_upon_entry,omitempty"`
+	WaitingRoom      *bool    `json:"waiting_room,omitempty"`
+	AutoRecording    string   `json:"auto_recording,omitempty"`
+	AlternativeHosts []string `json:"alternative_hosts,omitempty"` // Email addresses
+	ApprovalType     *int     `json:"approval_type,omitempty"`
+}
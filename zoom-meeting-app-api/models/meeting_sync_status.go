@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Meeting sync outcomes
+const (
+	SyncStatusOK     = "ok"
+	SyncStatusFailed = "failed"
+)
+
+// MeetingSyncStatus records the last reconciliation of a user's meetings with Zoom
+type MeetingSyncStatus struct {
+	gorm.Model
+	UserID        uint       `json:"user_id" gorm:"uniqueIndex"`
+	LastSyncAt    time.Time  `json:"last_sync_at"`
+	LastSuccessAt *time.Time `json:"last_success_at"`
+	Status        string     `json:"status"`
+	Error         string     `json:"error"`
+
+	// Changes made by the last successful sync
+	Added   int `json:"added"`
+	Updated int `json:"updated"`
+	Removed int `json:"removed"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import "gorm.io/gorm"
+
+// MeetingTemplate holds the defaults a user applies when creating similar
+// meetings. TopicPattern may contain {date}, {time} and {weekday}, which are
+// filled in from the meeting's start in its time zone.
+type MeetingTemplate struct {
+	gorm.Model
+	Name         string           `json:"name"`
+	TopicPattern string           `json:"topic_pattern"`
+	Duration     int              `json:"duration"` // Minutes, 0 uses the meeting default
+	Timezone     string           `json:"timezone"`
+	Agenda       string           `json:"agenda"`
+	Settings     *MeetingSettings `json:"settings" gorm:"serializer:json"`
+	Invitees     []string         `json:"invitees" gorm:"serializer:json"` // Email addresses
+	UserID       uint             `json:"user_id" gorm:"index"`
+	User         User             `json:"-" gorm:"foreignKey:UserID"`
+}
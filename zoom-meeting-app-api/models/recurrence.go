@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Recurrence types
+const (
+	RecurrenceDaily   = "daily"
+	RecurrenceWeekly  = "weekly"
+	RecurrenceMonthly = "monthly"
+)
+
+// Recurrence is the repeat rule of a recurring meeting, stored as JSON on the meeting.
+// Weekdays are lowercase English names ("monday"). A monthly rule repeats either on
+// MonthlyDay or on the MonthlyWeek-th (-1 for last) MonthlyWeekDay of the month.
+// The series ends after EndTimes occurrences or at EndDateTime.
+type Recurrence struct {
+	Type           string     `json:"type"`
+	RepeatInterval int        `json:"repeat_interval"`
+	WeeklyDays     []string   `json:"weekly_days,omitempty"`
+	MonthlyDay     int        `json:"monthly_day,omitempty"`
+	MonthlyWeek    int        `json:"monthly_week,omitempty"`
+	MonthlyWeekDay string     `json:"monthly_week_day,omitempty"`
+	EndTimes       int        `json:"end_times,omitempty"`
+	EndDateTime    *time.Time `json:"end_date_time,omitempty"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// RefreshToken can be exchanged once for a new access token and a new
+// refresh token of the same family. Only a SHA-256 hash of the token is
+// stored. Presenting a token that was already exchanged means it leaked, and
+// revokes its whole family.
+type RefreshToken struct {
+	ID            uint `gorm:"primarykey"`
+	CreatedAt     time.Time
+	UserID        uint       `gorm:"not null;index"`
+	TokenHash     string     `gorm:"not null;uniqueIndex"`
+	FamilyID      string     `gorm:"not null;index"` // Shared by all tokens rotated from the same login
+	AccessTokenID string     // jti of the access token issued together with this token
+	ExpiresAt     time.Time  `gorm:"not null"`
+	UsedAt        *time.Time // Set when the token is exchanged
+	RevokedAt     *time.Time // Set on logout or reuse detection
+}
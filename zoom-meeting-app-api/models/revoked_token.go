@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RevokedToken is an access token rejected before it expires, identified by
+// its jti. Rows are useless once ExpiresAt has passed and can be deleted.
+type RevokedToken struct {
+	JTI       string    `gorm:"primaryKey"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+}
+
+// TokenRevoked reports whether the access token with the given jti has been revoked
+func TokenRevoked(db *gorm.DB, jti string) bool {
+	var count int64
+	db.Model(&RevokedToken{}).Where("jti = ?", jti).Count(&count)
+	return count > 0
+}
@@ -0,0 +1,50 @@
+package models
+
+// Role determines what a user is allowed to do
+type Role string
+
+const (
+	RoleAdmin     Role = "admin"     // Manages users and every meeting
+	RoleOrganizer Role = "organizer" // Manages every meeting
+	RoleMember    Role = "member"    // Manages their own meetings
+	RoleViewer    Role = "viewer"    // Only views meetings
+)
+
+// DefaultRole is given to newly registered users
+const DefaultRole = RoleMember
+
+// Permission is an action guarded by a role
+type Permission string
+
+const (
+	PermissionViewMeetings   Permission = "meetings:view"   // List and view meetings and sync status
+	PermissionEditMeetings   Permission = "meetings:edit"   // Create, update and delete meetings and templates
+	PermissionManageMeetings Permission = "meetings:manage" // Act on meetings owned by other users
+	PermissionManageUsers    Permission = "users:manage"    // List users, change roles and deactivate accounts
+)
+
+var rolePermissions = map[Role][]Permission{
+	RoleAdmin:     {PermissionViewMeetings, PermissionEditMeetings, PermissionManageMeetings, PermissionManageUsers},
+	RoleOrganizer: {PermissionViewMeetings, PermissionEditMeetings, PermissionManageMeetings},
+	RoleMember:    {PermissionViewMeetings, PermissionEditMeetings},
+	RoleViewer:    {PermissionViewMeetings},
+}
+
+// Roles lists the valid roles from most to least privileged
+var Roles = []Role{RoleAdmin, RoleOrganizer, RoleMember, RoleViewer}
+
+// Valid reports whether r is a known role
+func (r Role) Valid() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+// Can reports whether the role grants permission p
+func (r Role) Can(p Permission) bool {
+	for _, granted := range rolePermissions[r] {
+		if granted == p {
+			return true
+		}
+	}
+	return false
+}
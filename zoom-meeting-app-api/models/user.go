@@ -1,6 +1,7 @@
 package models
 
 import (
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -8,12 +9,37 @@ import (
 
 type User struct {
 	gorm.Model
-	Name        string    `json:"name"`
-	Email       string    `json:"email" gorm:"unique"`
-	Password    string    `json:"-"`
-	ZoomToken   string    `json:"-"`
-	ZoomRefresh string    `json:"-"`
-	ZoomAccount string    `json:"zoom_account"`
-	ZoomExpires time.Time `json:"zoom_expires"`
-	IdZoom      string    `json:"id_zoom"`
+	Name            string     `json:"name"`
+	Email           string     `json:"email" gorm:"unique;uniqueIndex:idx_users_email_lower,expression:lower(email)"` // Stored normalized, see NormalizeEmail
+	Password        string     `json:"-"`
+	ZoomToken       string     `json:"-"`
+	ZoomRefresh     string     `json:"-"`
+	ZoomAccount     string     `json:"zoom_account"`
+	ZoomExpires     time.Time  `json:"zoom_expires"`
+	IdZoom          string     `json:"id_zoom"`
+	Timezone        string     `json:"timezone" gorm:"default:UTC"` // IANA time zone used when a request does not specify one
+	Role            Role       `json:"role" gorm:"type:varchar(20);not null;default:member"`
+	DeactivatedAt   *time.Time `json:"deactivated_at,omitempty"`    // Deactivated users cannot sign in
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"` // Set once the user follows the verification link
+}
+
+// NormalizeEmail returns the form email addresses are stored and looked up
+// in, so addresses differing only in case or surrounding spaces match
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// Can reports whether the user's role grants permission p
+func (u User) Can(p Permission) bool {
+	return u.Role.Can(p)
+}
+
+// Active reports whether the user's account has not been deactivated
+func (u User) Active() bool {
+	return u.DeactivatedAt == nil
+}
+
+// EmailVerified reports whether the user has verified their email address
+func (u User) EmailVerified() bool {
+	return u.EmailVerifiedAt != nil
 }
@@ -3,6 +3,6 @@ package main
 import "zoom-meeting-app/database"
 
 func ResetDb() {
-	database.ConnectDatabase()
+	database.ConnectDatabase(loadConfig().Database)
 	database.ResetDatabase()
 }
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"zoom-meeting-app/controllers"
+	"zoom-meeting-app/middleware"
+	"zoom-meeting-app/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func AdminRoutes(r *gin.Engine) {
+	adminRoutes := r.Group("/admin", middleware.AuthMiddleware(), middleware.RequireRole(models.RoleAdmin))
+	{
+		adminRoutes.GET("/users", controllers.GetUsers)
+		adminRoutes.PUT("/users/:id/role", controllers.UpdateUserRole)
+		adminRoutes.POST("/users/:id/deactivate", controllers.DeactivateUser)
+		adminRoutes.POST("/users/:id/reactivate", controllers.ReactivateUser)
+	}
+}
@@ -1,19 +1,28 @@
 package routes
 
 import (
+	"zoom-meeting-app/config"
 	"zoom-meeting-app/controllers"
 	"zoom-meeting-app/middleware"
 
 	"github.com/gin-gonic/gin"
 )
 
-func AuthRoutes(r *gin.Engine) {
+func AuthRoutes(r *gin.Engine, cfg *config.Config) {
 	auth := r.Group("/auth")
 	{
 		auth.POST("/register", controllers.Register)
 		auth.POST("/login", controllers.Login)
+		auth.POST("/refresh", controllers.RefreshToken)
+		auth.POST("/logout", middleware.AuthMiddleware(), controllers.Logout)
+		auth.POST("/forgot-password", controllers.ForgotPassword)
+		auth.POST("/reset-password", controllers.ResetPassword)
+		auth.GET("/verify-email", controllers.VerifyEmail)
 		auth.GET("/zoom", middleware.AuthMiddleware(), controllers.RedirectToZoom)
-		auth.GET("/callback", controllers.ZoomCallback)
 		auth.GET("/me", middleware.AuthMiddleware(), controllers.Me)
+		auth.PATCH("/me", middleware.AuthMiddleware(), controllers.UpdateMe)
 	}
+
+	// Zoom OAuth callback, must match the redirect URI registered in the Zoom app
+	r.GET(cfg.Zoom.CallbackPath, controllers.ZoomCallback)
 }
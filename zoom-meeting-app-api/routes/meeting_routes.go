@@ -3,17 +3,26 @@ package routes
 import (
 	"zoom-meeting-app/controllers"
 	"zoom-meeting-app/middleware"
+	"zoom-meeting-app/models"
 
 	"github.com/gin-gonic/gin"
 )
 
 func MeetingRoutes(r *gin.Engine) {
+	view := middleware.RequirePermission(models.PermissionViewMeetings)
+	edit := middleware.RequirePermission(models.PermissionEditMeetings)
+
 	meetingRoutes := r.Group("/meetings")
 	{
-		meetingRoutes.POST("/", middleware.AuthMiddleware(), controllers.CreateMeeting)
-		meetingRoutes.GET("/", middleware.AuthMiddleware(), controllers.GetMeetings)
-		meetingRoutes.GET("/:id", middleware.AuthMiddleware(), controllers.GetMeetingByID)
-		meetingRoutes.PUT("/:id", middleware.AuthMiddleware(), controllers.UpdateMeeting)
-		meetingRoutes.DELETE("/:id", middleware.AuthMiddleware(), controllers.DeleteMeeting)
+		meetingRoutes.POST("/", middleware.AuthMiddleware(), edit, controllers.CreateMeeting)
+		meetingRoutes.GET("/", middleware.AuthMiddleware(), view, controllers.GetMeetings)
+		meetingRoutes.GET("/sync", middleware.AuthMiddleware(), view, controllers.GetSyncStatus)
+		meetingRoutes.POST("/sync", middleware.AuthMiddleware(), edit, controllers.SyncMeetings)
+		meetingRoutes.GET("/:id", middleware.AuthMiddleware(), view, controllers.GetMeetingByID)
+		meetingRoutes.PUT("/:id", middleware.AuthMiddleware(), edit, controllers.UpdateMeeting)
+		meetingRoutes.PATCH("/:id", middleware.AuthMiddleware(), edit, controllers.PatchMeeting)
+		meetingRoutes.DELETE("/:id", middleware.AuthMiddleware(), edit, controllers.DeleteMeeting)
+		meetingRoutes.PUT("/:id/occurrences/:occurrence_id", middleware.AuthMiddleware(), edit, controllers.UpdateOccurrence)
+		meetingRoutes.DELETE("/:id/occurrences/:occurrence_id", middleware.AuthMiddleware(), edit, controllers.DeleteOccurrence)
 	}
 }
@@ -1,10 +1,16 @@
 package routes
 
 import (
+	"zoom-meeting-app/config"
+
 	"github.com/gin-gonic/gin"
 )
 
-func SetupRouter(r *gin.Engine) {
-	AuthRoutes(r)
+func SetupRouter(r *gin.Engine, cfg *config.Config) {
+	AuthRoutes(r, cfg)
 	MeetingRoutes(r)
-}
\ No newline at end of file
+	TemplateRoutes(r)
+	WebhookRoutes(r)
+	AdminRoutes(r)
+	WellKnownRoutes(r)
+}
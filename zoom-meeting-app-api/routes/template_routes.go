@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"zoom-meeting-app/controllers"
+	"zoom-meeting-app/middleware"
+	"zoom-meeting-app/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TemplateRoutes(r *gin.Engine) {
+	edit := middleware.RequirePermission(models.PermissionEditMeetings)
+
+	templateRoutes := r.Group("/templates")
+	{
+		templateRoutes.POST("/", middleware.AuthMiddleware(), edit, controllers.CreateTemplate)
+		templateRoutes.GET("/", middleware.AuthMiddleware(), edit, controllers.GetTemplates)
+		templateRoutes.GET("/:id", middleware.AuthMiddleware(), edit, controllers.GetTemplateByID)
+		templateRoutes.PUT("/:id", middleware.AuthMiddleware(), edit, controllers.UpdateTemplate)
+		templateRoutes.DELETE("/:id", middleware.AuthMiddleware(), edit, controllers.DeleteTemplate)
+	}
+}
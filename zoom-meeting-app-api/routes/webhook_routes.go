@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"zoom-meeting-app/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookRoutes registers the endpoints called by Zoom. They are
+// authenticated by signature instead of the user JWT.
+func WebhookRoutes(r *gin.Engine) {
+	webhookRoutes := r.Group("/webhooks")
+	{
+		webhookRoutes.POST("/zoom", controllers.ZoomWebhook)
+	}
+}
@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"zoom-meeting-app/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func WellKnownRoutes(r *gin.Engine) {
+	wellKnown := r.Group("/.well-known")
+	{
+		wellKnown.GET("/jwks.json", controllers.GetJWKS)
+	}
+}
@@ -3,6 +3,6 @@ package main
 import "zoom-meeting-app/database"
 
 func RunsSeed() {
-	database.ConnectDatabase()
+	database.ConnectDatabase(loadConfig().Database)
 	database.SeedDatabase()
 }
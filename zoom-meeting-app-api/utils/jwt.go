@@ -1,40 +1,116 @@
 package utils
 
 import (
+	"errors"
+	"strconv"
 	"time"
+	"zoom-meeting-app/config"
 
-	"github.com/dgrijalva/jwt-go"
+	"github.com/golang-jwt/jwt/v5"
 )
 
-var jwtKey = []byte("your_secret_key")
+var (
+	jwtKeys         *KeyManager
+	jwtParser       *jwt.Parser
+	jwtIssuer       string
+	jwtAudience     string
+	accessTokenTTL  time.Duration
+	refreshTokenTTL time.Duration
+)
+
+// InitJWT loads the keys used to sign and validate access tokens and sets
+// their issuer, audience and lifetime, and the lifetime of refresh tokens
+func InitJWT(cfg config.JWTConfig) error {
+	keys, err := NewKeyManager(cfg)
+	if err != nil {
+		return err
+	}
+
+	jwtKeys = keys
+	jwtIssuer = cfg.Issuer
+	jwtAudience = cfg.Audience
+	jwtParser = jwt.NewParser(
+		jwt.WithValidMethods(SigningAlgorithms()),
+		jwt.WithIssuer(cfg.Issuer),
+		jwt.WithAudience(cfg.Audience),
+		jwt.WithLeeway(cfg.Leeway),
+		jwt.WithIssuedAt(),
+		jwt.WithExpirationRequired(),
+	)
+	accessTokenTTL = cfg.AccessTokenTTL
+	refreshTokenTTL = cfg.RefreshTokenTTL
+	return nil
+}
 
+// JWTKeys returns the keys access tokens are signed and verified with
+func JWTKeys() *KeyManager {
+	return jwtKeys
+}
+
+// AccessTokenTTL returns how long access tokens are valid
+func AccessTokenTTL() time.Duration {
+	return accessTokenTTL
+}
+
+// RefreshTokenTTL returns how long refresh tokens are valid
+func RefreshTokenTTL() time.Duration {
+	return refreshTokenTTL
+}
+
+// Claims are the claims of an access token. The user is identified by the
+// subject; UserID is parsed from it when the token is validated.
 type Claims struct {
-	UserID uint `json:"user_id"`
-	jwt.StandardClaims
+	UserID uint `json:"-"`
+	jwt.RegisteredClaims
 }
 
-func GenerateToken(userID uint) (string, error) {
-	expirationTime := time.Now().Add(24 * time.Hour)
+// GenerateToken issues a short-lived access token with a unique ID (jti),
+// returning its claims so the ID can be recorded
+func GenerateToken(userID uint) (string, *Claims, error) {
+	tokenID, err := NewRandomToken(16)
+	if err != nil {
+		return "", nil, err
+	}
+
+	now := time.Now()
 	claims := &Claims{
 		UserID: userID,
-		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: expirationTime.Unix(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID,
+			Issuer:    jwtIssuer,
+			Audience:  jwt.ClaimStrings{jwtAudience},
+			Subject:   strconv.FormatUint(uint64(userID), 10),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(accessTokenTTL)),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtKey)
+	signed, err := jwtKeys.Sign(claims)
+	if err != nil {
+		return "", nil, err
+	}
+	return signed, claims, nil
 }
 
+// ValidateToken checks the signature, issuer, audience and times of an access
+// token, allowing the configured clock skew, and returns its claims
 func ValidateToken(tokenString string) (*Claims, error) {
 	claims := &Claims{}
-	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		return jwtKey, nil
-	})
-
-	if err != nil || !token.Valid {
+	if _, err := jwtParser.ParseWithClaims(tokenString, claims, jwtKeys.Keyfunc); err != nil {
 		return nil, err
 	}
 
+	// Tokens without an ID cannot be revoked
+	if claims.ID == "" {
+		return nil, errors.New("token has no ID")
+	}
+
+	userID, err := strconv.ParseUint(claims.Subject, 10, 0)
+	if err != nil || userID == 0 {
+		return nil, errors.New("token has no valid subject")
+	}
+	claims.UserID = uint(userID)
+
 	return claims, nil
 }
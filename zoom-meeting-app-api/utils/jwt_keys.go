@@ -0,0 +1,254 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"sync"
+	"zoom-meeting-app/config"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// signingMethods lists the algorithms keys may use. Tokens signed with any
+// other algorithm, including "none", are rejected.
+var signingMethods = map[string]jwt.SigningMethod{
+	"HS256": jwt.SigningMethodHS256,
+	"HS384": jwt.SigningMethodHS384,
+	"HS512": jwt.SigningMethodHS512,
+	"RS256": jwt.SigningMethodRS256,
+	"RS384": jwt.SigningMethodRS384,
+	"RS512": jwt.SigningMethodRS512,
+	"EdDSA": jwt.SigningMethodEdDSA,
+}
+
+// SigningAlgorithms returns the algorithms keys may use
+func SigningAlgorithms() []string {
+	algorithms := make([]string, 0, len(signingMethods))
+	for alg := range signingMethods {
+		algorithms = append(algorithms, alg)
+	}
+	sort.Strings(algorithms)
+	return algorithms
+}
+
+// SigningKey is a key that verifies access tokens, and signs them when its
+// private part is known
+type SigningKey struct {
+	ID     string
+	Method jwt.SigningMethod
+
+	signKey   interface{} // []byte, *rsa.PrivateKey or ed25519.PrivateKey; nil for verification only
+	verifyKey interface{} // []byte, *rsa.PublicKey or ed25519.PublicKey
+}
+
+// CanSign reports whether the key can sign new tokens
+func (k *SigningKey) CanSign() bool {
+	return k.signKey != nil
+}
+
+// KeyManager holds the keys access tokens are signed and verified with.
+// Every token carries the ID of its key in the kid header, so the active
+// key can be rotated while tokens signed with earlier keys stay valid.
+type KeyManager struct {
+	mu     sync.RWMutex
+	keys   map[string]*SigningKey
+	active string
+}
+
+// NewKeyManager loads the keys of the configuration
+func NewKeyManager(cfg config.JWTConfig) (*KeyManager, error) {
+	m := &KeyManager{keys: make(map[string]*SigningKey)}
+
+	if cfg.Secret != "" {
+		if err := m.AddKey(config.JWTKeyConfig{ID: config.DefaultJWTKeyID, Algorithm: "HS256", Secret: cfg.Secret}); err != nil {
+			return nil, err
+		}
+	}
+	for _, keyConfig := range cfg.Keys {
+		if err := m.AddKey(keyConfig); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := m.SetActive(cfg.ActiveKey); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// AddKey loads a key and makes it available for verification
+func (m *KeyManager) AddKey(cfg config.JWTKeyConfig) error {
+	key, err := loadSigningKey(cfg)
+	if err != nil {
+		return fmt.Errorf("JWT key %q: %w", cfg.ID, err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, exists := m.keys[key.ID]; exists {
+		return fmt.Errorf("JWT key %q is already loaded", key.ID)
+	}
+	m.keys[key.ID] = key
+	return nil
+}
+
+// SetActive makes the key with the given ID sign new tokens
+func (m *KeyManager) SetActive(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key, ok := m.keys[id]
+	if !ok {
+		return fmt.Errorf("JWT key %q is not loaded", id)
+	}
+	if !key.CanSign() {
+		return fmt.Errorf("JWT key %q has no private key and cannot sign tokens", id)
+	}
+	m.active = id
+	return nil
+}
+
+// Sign signs claims with the active key
+func (m *KeyManager) Sign(claims jwt.Claims) (string, error) {
+	m.mu.RLock()
+	key := m.keys[m.active]
+	m.mu.RUnlock()
+
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.signKey)
+}
+
+// Keyfunc returns the key that verifies a token, after checking that the
+// token's algorithm is the one of the key named by its kid header
+func (m *KeyManager) Keyfunc(token *jwt.Token) (interface{}, error) {
+	id, _ := token.Header["kid"].(string)
+	m.mu.RLock()
+	key, ok := m.keys[id]
+	m.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", id)
+	}
+	if token.Method == nil || token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %v for key %q", token.Header["alg"], id)
+	}
+	return key.verifyKey, nil
+}
+
+// JWK is a public key in JSON Web Key format (RFC 7517)
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+
+	Curve string `json:"crv,omitempty"` // Ed25519
+	X     string `json:"x,omitempty"`   // Ed25519 public key
+	N     string `json:"n,omitempty"`   // RSA modulus
+	E     string `json:"e,omitempty"`   // RSA exponent
+}
+
+// JWKS returns the public keys of the asymmetric keys, so other services can
+// verify tokens. HMAC secrets are never published.
+func (m *KeyManager) JWKS() []JWK {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	jwks := []JWK{}
+	for _, key := range m.keys {
+		jwk := JWK{KeyID: key.ID, Use: "sig", Algorithm: key.Method.Alg()}
+		switch public := key.verifyKey.(type) {
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		default:
+			continue
+		}
+		jwks = append(jwks, jwk)
+	}
+	sort.Slice(jwks, func(i, j int) bool { return jwks[i].KeyID < jwks[j].KeyID })
+	return jwks
+}
+
+// loadSigningKey reads a key and checks it suits its algorithm
+func loadSigningKey(cfg config.JWTKeyConfig) (*SigningKey, error) {
+	method, ok := signingMethods[cfg.Algorithm]
+	if !ok {
+		return nil, fmt.Errorf("unsupported algorithm %q", cfg.Algorithm)
+	}
+	key := &SigningKey{ID: cfg.ID, Method: method}
+
+	if _, hmac := method.(*jwt.SigningMethodHMAC); hmac {
+		if cfg.Secret == "" {
+			return nil, errors.New("HMAC keys need a secret")
+		}
+		key.signKey = []byte(cfg.Secret)
+		key.verifyKey = key.signKey
+		return key, nil
+	}
+
+	if cfg.KeyFile == "" {
+		return nil, fmt.Errorf("%s keys need a key file", cfg.Algorithm)
+	}
+	data, err := os.ReadFile(cfg.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := parsePEMKey(data)
+	if err != nil {
+		return nil, err
+	}
+
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.signKey, key.verifyKey = k, &k.PublicKey
+	case *rsa.PublicKey:
+		key.verifyKey = k
+	case ed25519.PrivateKey:
+		key.signKey, key.verifyKey = k, k.Public()
+	case ed25519.PublicKey:
+		key.verifyKey = k
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", parsed)
+	}
+
+	_, rsaKey := key.verifyKey.(*rsa.PublicKey)
+	if _, rsaMethod := method.(*jwt.SigningMethodRSA); rsaKey != rsaMethod {
+		return nil, fmt.Errorf("key file does not hold a key for %s", cfg.Algorithm)
+	}
+	return key, nil
+}
+
+// parsePEMKey parses a PKCS #8 or PKCS #1 private key, or a PKIX or PKCS #1
+// public key
+func parsePEMKey(data []byte) (interface{}, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("key file is not PEM encoded")
+	}
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		return x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	}
+	return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+}
@@ -0,0 +1,152 @@
+package utils_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"zoom-meeting-app/config"
+	"zoom-meeting-app/utils"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// writeKey stores a private or public key as PEM and returns its path
+func writeKey(t *testing.T, name string, key interface{}) string {
+	t.Helper()
+
+	var block *pem.Block
+	switch k := key.(type) {
+	case *rsa.PublicKey, ed25519.PublicKey:
+		der, err := x509.MarshalPKIXPublicKey(k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		block = &pem.Block{Type: "PUBLIC KEY", Bytes: der}
+	default:
+		der, err := x509.MarshalPKCS8PrivateKey(k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		block = &pem.Block{Type: "PRIVATE KEY", Bytes: der}
+	}
+
+	path := filepath.Join(t.TempDir(), name+".pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(block), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func parse(m *utils.KeyManager, token string) error {
+	_, err := jwt.Parse(token, m.Keyfunc)
+	return err
+}
+
+func TestKeyManagerRotation(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.JWTConfig{
+		Secret:    "default-secret",
+		ActiveKey: config.DefaultJWTKeyID,
+		Keys: []config.JWTKeyConfig{
+			{ID: "rsa-1", Algorithm: "RS256", KeyFile: writeKey(t, "rsa", rsaKey)},
+			{ID: "ed-1", Algorithm: "EdDSA", KeyFile: writeKey(t, "ed", edKey)},
+		},
+	}
+	m, err := utils.NewKeyManager(cfg)
+	if err != nil {
+		t.Fatalf("NewKeyManager: %v", err)
+	}
+
+	claims := jwt.MapClaims{"sub": "1"}
+	var tokens []string
+	for _, id := range []string{config.DefaultJWTKeyID, "rsa-1", "ed-1"} {
+		if err := m.SetActive(id); err != nil {
+			t.Fatalf("SetActive(%s): %v", id, err)
+		}
+		token, err := m.Sign(claims)
+		if err != nil {
+			t.Fatalf("Sign with %s: %v", id, err)
+		}
+		tokens = append(tokens, token)
+	}
+
+	// Tokens signed before each rotation still verify
+	for i, token := range tokens {
+		if err := parse(m, token); err != nil {
+			t.Errorf("token %d no longer verifies: %v", i, err)
+		}
+	}
+
+	jwks := m.JWKS()
+	if len(jwks) != 2 || jwks[0].KeyID != "ed-1" || jwks[0].KeyType != "OKP" || jwks[1].KeyID != "rsa-1" || jwks[1].KeyType != "RSA" {
+		t.Errorf("JWKS = %+v, want only the Ed25519 and RSA keys", jwks)
+	}
+}
+
+func TestKeyManagerRejectsUnexpectedAlgorithms(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicPath := writeKey(t, "rsa-public", &rsaKey.PublicKey)
+	publicPEM, err := os.ReadFile(publicPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := utils.NewKeyManager(config.JWTConfig{
+		Secret:    "secret",
+		ActiveKey: config.DefaultJWTKeyID,
+		Keys:      []config.JWTKeyConfig{{ID: "rsa-old", Algorithm: "RS256", KeyFile: publicPath}},
+	})
+	if err != nil {
+		t.Fatalf("NewKeyManager: %v", err)
+	}
+
+	sign := func(method jwt.SigningMethod, kid string, key interface{}) string {
+		token := jwt.NewWithClaims(method, jwt.MapClaims{"sub": "1"})
+		if kid != "" {
+			token.Header["kid"] = kid
+		}
+		signed, err := token.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"HS256 signed with the RSA public key", sign(jwt.SigningMethodHS256, "rsa-old", publicPEM)},
+		{"HS512 for an HS256 key", sign(jwt.SigningMethodHS512, config.DefaultJWTKeyID, []byte("secret"))},
+		{"unsigned", sign(jwt.SigningMethodNone, config.DefaultJWTKeyID, jwt.UnsafeAllowNoneSignatureType)},
+		{"unknown key", sign(jwt.SigningMethodHS256, "missing", []byte("secret"))},
+		{"without kid", sign(jwt.SigningMethodHS256, "", []byte("secret"))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := parse(m, tt.token); err == nil {
+				t.Fatal("token accepted")
+			}
+		})
+	}
+
+	if err := m.SetActive("rsa-old"); err == nil {
+		t.Error("a key without private part became active")
+	}
+}
@@ -0,0 +1,133 @@
+package utils_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"os"
+	"strings"
+	"testing"
+	"time"
+	"zoom-meeting-app/config"
+	"zoom-meeting-app/utils"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestValidateToken(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicPEM, err := os.ReadFile(writeKey(t, "rsa-public", &rsaKey.PublicKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = utils.InitJWT(config.JWTConfig{
+		Secret:          "secret",
+		ActiveKey:       config.DefaultJWTKeyID,
+		Keys:            []config.JWTKeyConfig{{ID: "rsa-1", Algorithm: "RS256", KeyFile: writeKey(t, "rsa", rsaKey)}},
+		Issuer:          "https://api.example.com",
+		Audience:        "zoom-meeting-app",
+		Leeway:          30 * time.Second,
+		AccessTokenTTL:  15 * time.Minute,
+		RefreshTokenTTL: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("InitJWT: %v", err)
+	}
+
+	valid, issued, err := utils.GenerateToken(42)
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+
+	now := time.Now()
+	// claims returns valid claims, changed by edit
+	claims := func(edit func(*jwt.RegisteredClaims)) jwt.RegisteredClaims {
+		c := jwt.RegisteredClaims{
+			ID:        "token-id",
+			Issuer:    "https://api.example.com",
+			Audience:  jwt.ClaimStrings{"zoom-meeting-app"},
+			Subject:   "42",
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+		}
+		if edit != nil {
+			edit(&c)
+		}
+		return c
+	}
+	// sign signs claims with the active key, as GenerateToken does
+	sign := func(c jwt.RegisteredClaims) string {
+		signed, err := utils.JWTKeys().Sign(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+	// signWith signs claims with any method and key under the given kid
+	signWith := func(method jwt.SigningMethod, kid string, key interface{}) string {
+		token := jwt.NewWithClaims(method, claims(nil))
+		token.Header["kid"] = kid
+		signed, err := token.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+	// tamper replaces the payload of a token, keeping its signature
+	tamper := func(token string, c jwt.RegisteredClaims) string {
+		parts := strings.Split(token, ".")
+		forged := strings.Split(sign(c), ".")
+		return parts[0] + "." + forged[1] + "." + parts[2]
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		ok    bool
+	}{
+		{"generated", valid, true},
+		{"expired within leeway", sign(claims(func(c *jwt.RegisteredClaims) { c.ExpiresAt = jwt.NewNumericDate(now.Add(-10 * time.Second)) })), true},
+		{"signed with a rotated key", signWith(jwt.SigningMethodRS256, "rsa-1", rsaKey), true},
+
+		{"expired", sign(claims(func(c *jwt.RegisteredClaims) { c.ExpiresAt = jwt.NewNumericDate(now.Add(-time.Minute)) })), false},
+		{"without expiry", sign(claims(func(c *jwt.RegisteredClaims) { c.ExpiresAt = nil })), false},
+		{"not valid yet", sign(claims(func(c *jwt.RegisteredClaims) { c.NotBefore = jwt.NewNumericDate(now.Add(time.Minute)) })), false},
+		{"issued in the future", sign(claims(func(c *jwt.RegisteredClaims) { c.IssuedAt = jwt.NewNumericDate(now.Add(time.Minute)) })), false},
+		{"tampered payload", tamper(valid, claims(func(c *jwt.RegisteredClaims) { c.Subject = "1" })), false},
+		{"tampered signature", valid[:len(valid)-4] + "AAAA", false},
+		{"wrong algorithm for key", signWith(jwt.SigningMethodHS512, config.DefaultJWTKeyID, []byte("secret")), false},
+		{"HMAC signed with the RSA public key", signWith(jwt.SigningMethodHS256, "rsa-1", publicPEM), false},
+		{"unsigned", signWith(jwt.SigningMethodNone, config.DefaultJWTKeyID, jwt.UnsafeAllowNoneSignatureType), false},
+		{"wrong audience", sign(claims(func(c *jwt.RegisteredClaims) { c.Audience = jwt.ClaimStrings{"other-app"} })), false},
+		{"without audience", sign(claims(func(c *jwt.RegisteredClaims) { c.Audience = nil })), false},
+		{"wrong issuer", sign(claims(func(c *jwt.RegisteredClaims) { c.Issuer = "https://evil.example.com" })), false},
+		{"without ID", sign(claims(func(c *jwt.RegisteredClaims) { c.ID = "" })), false},
+		{"without subject", sign(claims(func(c *jwt.RegisteredClaims) { c.Subject = "" })), false},
+		{"non-numeric subject", sign(claims(func(c *jwt.RegisteredClaims) { c.Subject = "admin" })), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := utils.ValidateToken(tt.token)
+			if !tt.ok {
+				if err == nil {
+					t.Fatalf("token accepted with claims %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ValidateToken: %v", err)
+			}
+			if got.UserID != 42 {
+				t.Errorf("UserID = %d, want 42", got.UserID)
+			}
+		})
+	}
+
+	if issued.Issuer != "https://api.example.com" || issued.Subject != "42" || issued.NotBefore == nil || issued.IssuedAt == nil {
+		t.Errorf("GenerateToken claims = %+v", issued.RegisteredClaims)
+	}
+}
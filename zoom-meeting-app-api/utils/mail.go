@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"log"
+	"zoom-meeting-app/config"
+	"zoom-meeting-app/mail"
+)
+
+var mailer mail.Sender
+
+// InitMail sets up the shared mail sender, which logs emails instead of
+// sending them when no SMTP server is configured
+func InitMail(cfg config.MailConfig) {
+	if cfg.SMTPHost == "" {
+		log.Println("SMTP_HOST is not set, emails are written to the log")
+		SetMailer(mail.LogSender{})
+		return
+	}
+
+	SetMailer(&mail.SMTPSender{
+		Host:     cfg.SMTPHost,
+		Port:     cfg.SMTPPort,
+		Username: cfg.Username,
+		Password: cfg.Password,
+		From:     cfg.From,
+	})
+}
+
+// Mailer returns the shared mail sender
+func Mailer() mail.Sender {
+	return mailer
+}
+
+// SetMailer replaces the shared mail sender, e.g. with a mail.MemorySender
+func SetMailer(sender mail.Sender) {
+	mailer = sender
+}
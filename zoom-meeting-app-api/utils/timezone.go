@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultTimezone is used when neither the request nor the user specify a time zone
+const DefaultTimezone = "UTC"
+
+// localTimeLayouts are accepted for start times without a UTC offset,
+// which are interpreted as wall-clock time in the meeting's time zone
+var localTimeLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+}
+
+// LoadTimezone resolves an IANA time zone name such as "Asia/Jakarta"
+func LoadTimezone(name string) (*time.Location, error) {
+	if name == "" {
+		return nil, fmt.Errorf("time zone is required")
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q", name)
+	}
+	return loc, nil
+}
+
+// ParseStartTime parses a meeting start time. Values with a UTC offset
+// (RFC 3339) are absolute; values without one are read in loc.
+func ParseStartTime(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+	for _, layout := range localTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid start_time %q, expected RFC 3339 or YYYY-MM-DDTHH:MM:SS", value)
+}
+
+// ParseDateBound parses the bound of a date range filter. A date without a
+// time (YYYY-MM-DD) is the start of that day in loc, or with endOfDay the
+// start of the next day, so that the whole day is included.
+func ParseDateBound(value string, loc *time.Location, endOfDay bool) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", value, loc); err == nil {
+		if endOfDay {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t.UTC(), nil
+	}
+	if t, err := ParseStartTime(value, loc); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q, expected YYYY-MM-DD or RFC 3339", value)
+}
@@ -0,0 +1,95 @@
+package zoom
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// DefaultBaseURL is the base URL of the Zoom REST API v2
+const DefaultBaseURL = "https://api.zoom.us/v2"
+
+// Client is a Zoom REST API client with typed requests and responses. Every
+// call is made on behalf of an application user whose access token is
+// looked up through Tokens.
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+	Tokens     TokenSource
+}
+
+// NewClient creates a Client using the default base URL and HTTP client
+func NewClient(tokens TokenSource) *Client {
+	return &Client{
+		BaseURL:    DefaultBaseURL,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+		Tokens:     tokens,
+	}
+}
+
+// WithTokens returns a copy of the client that takes its tokens from tokens
+func (c *Client) WithTokens(tokens TokenSource) *Client {
+	clone := *c
+	clone.Tokens = tokens
+	return &clone
+}
+
+// do sends a request to the Zoom API and decodes the response body into out (if not nil).
+// Any status other than expectedStatus is returned as an *APIError.
+func (c *Client) do(ctx context.Context, userID uint, method, path string, body interface{}, expectedStatus int, out interface{}) error {
+	if c.Tokens == nil {
+		return fmt.Errorf("zoom: no token source configured")
+	}
+	accessToken, err := c.Tokens.Token(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get Zoom access token: %w", err)
+	}
+
+	var reqBody io.Reader
+	if body != nil {
+		jsonData, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(jsonData)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != expectedStatus {
+		return newAPIError(resp.StatusCode, respBody)
+	}
+
+	if out != nil && len(respBody) > 0 {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return fmt.Errorf("zoom: invalid response format: %w", err)
+		}
+	}
+
+	return nil
+}
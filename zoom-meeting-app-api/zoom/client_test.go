@@ -0,0 +1,280 @@
+package zoom_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+	"zoom-meeting-app/zoom"
+	"zoom-meeting-app/zoom/zoomtest"
+)
+
+func newTestClient(t *testing.T) (*zoomtest.Server, *zoom.Client) {
+	t.Helper()
+
+	srv := zoomtest.NewServer()
+	t.Cleanup(srv.Close)
+
+	token := srv.IssueToken(srv.DefaultUser().ID)
+	return srv, srv.NewClient(zoom.StaticToken(token.AccessToken))
+}
+
+func TestMeetingCRUD(t *testing.T) {
+	srv, client := newTestClient(t)
+	ctx := context.Background()
+
+	created, err := client.CreateMeeting(ctx, 1, &zoom.MeetingRequest{
+		Topic:     "Standup",
+		Type:      zoom.MeetingTypeScheduled,
+		StartTime: "2025-03-01T09:00:00Z",
+		Duration:  15,
+		Timezone:  "Asia/Jakarta",
+	})
+	if err != nil {
+		t.Fatalf("CreateMeeting: %v", err)
+	}
+	if created.ID == 0 || created.JoinURL == "" || created.Topic != "Standup" || created.Duration != 15 {
+		t.Fatalf("unexpected created meeting: %+v", created)
+	}
+
+	id := created.ID.String()
+	if err := client.UpdateMeeting(ctx, 1, id, &zoom.MeetingRequest{Topic: "Daily standup"}); err != nil {
+		t.Fatalf("UpdateMeeting: %v", err)
+	}
+
+	fetched, err := client.GetMeeting(ctx, 1, id)
+	if err != nil {
+		t.Fatalf("GetMeeting: %v", err)
+	}
+	if fetched.Topic != "Daily standup" || fetched.Duration != 15 || fetched.StartTime.IsZero() {
+		t.Fatalf("update not applied as a partial update: %+v", fetched)
+	}
+
+	meetings, err := client.ListMeetings(ctx, 1, "")
+	if err != nil {
+		t.Fatalf("ListMeetings: %v", err)
+	}
+	if len(meetings) != 1 || meetings[0].ID != created.ID {
+		t.Fatalf("unexpected meetings: %+v", meetings)
+	}
+
+	if err := client.DeleteMeeting(ctx, 1, id); err != nil {
+		t.Fatalf("DeleteMeeting: %v", err)
+	}
+	if _, ok := srv.Meeting(id); ok {
+		t.Fatal("meeting still stored after delete")
+	}
+
+	_, err = client.GetMeeting(ctx, 1, id)
+	var apiErr *zoom.APIError
+	if !errors.As(err, &apiErr) || !zoom.IsNotFound(err) || apiErr.Code != 3001 {
+		t.Fatalf("expected 404 APIError with code 3001, got %v", err)
+	}
+}
+
+func TestMeetingIDsAreEscaped(t *testing.T) {
+	srv, client := newTestClient(t)
+
+	_, err := client.GetMeeting(context.Background(), 1, "1/../../users/me?page_size=1")
+	if !zoom.IsNotFound(err) {
+		t.Fatalf("GetMeeting with a crafted ID: %v, want not found", err)
+	}
+	requests := srv.Requests()
+	last := requests[len(requests)-1]
+	if last.Path != "/v2/meetings/1/../../users/me?page_size=1" || len(last.Query) != 0 {
+		t.Errorf("request = %s ? %v, want the whole ID in the meeting path", last.Path, last.Query)
+	}
+}
+
+func TestOAuthExchangeAndRefresh(t *testing.T) {
+	srv := zoomtest.NewServer()
+	defer srv.Close()
+	ctx := context.Background()
+
+	oauth := srv.OAuth("http://localhost:8000/auth/callback")
+
+	noRedirect := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := noRedirect.Get(oauth.AuthCodeURL())
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("authorize status = %d, want 302", resp.StatusCode)
+	}
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatalf("invalid redirect: %v", err)
+	}
+
+	token, err := oauth.Exchange(ctx, location.Query().Get("code"))
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+
+	profile, err := srv.NewClient(zoom.StaticToken(token.AccessToken)).Me(ctx, 1)
+	if err != nil {
+		t.Fatalf("Me: %v", err)
+	}
+	if profile.ID != srv.DefaultUser().ID {
+		t.Fatalf("profile ID = %q, want %q", profile.ID, srv.DefaultUser().ID)
+	}
+
+	refreshed, err := oauth.Refresh(ctx, token.RefreshToken)
+	if err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if refreshed.RefreshToken == token.RefreshToken {
+		t.Fatal("refresh token was not rotated")
+	}
+	if _, err := oauth.Refresh(ctx, token.RefreshToken); err == nil {
+		t.Fatal("reusing a refresh token should fail")
+	}
+}
+
+func TestFailureInjection(t *testing.T) {
+	srv, client := newTestClient(t)
+	ctx := context.Background()
+
+	srv.Fail(zoomtest.Failure{
+		Method:  http.MethodPost,
+		Path:    "/v2/users/me/meetings",
+		Status:  http.StatusTooManyRequests,
+		Code:    429,
+		Message: "You have reached the maximum per-second rate limit for this API.",
+		Times:   1,
+	})
+
+	_, err := client.CreateMeeting(ctx, 1, &zoom.MeetingRequest{Topic: "Retro"})
+	var apiErr *zoom.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests || apiErr.Code != 429 {
+		t.Fatalf("expected injected 429, got %v", err)
+	}
+
+	if _, err := client.CreateMeeting(ctx, 1, &zoom.MeetingRequest{Topic: "Retro"}); err != nil {
+		t.Fatalf("failure should only apply once: %v", err)
+	}
+}
+
+func TestOccurrenceUpdateAndDelete(t *testing.T) {
+	_, client := newTestClient(t)
+	ctx := context.Background()
+
+	created, err := client.CreateMeeting(ctx, 1, &zoom.MeetingRequest{
+		Topic:      "Weekly sync",
+		Type:       zoom.MeetingTypeRecurringFixed,
+		StartTime:  "2025-03-03T02:00:00Z",
+		Duration:   30,
+		Timezone:   "Asia/Jakarta",
+		Recurrence: &zoom.Recurrence{Type: zoom.RecurrenceWeekly, RepeatInterval: 1, WeeklyDays: "2", EndTimes: 3},
+	})
+	if err != nil {
+		t.Fatalf("CreateMeeting: %v", err)
+	}
+	if len(created.Occurrences) != 3 {
+		t.Fatalf("occurrences = %d, want 3", len(created.Occurrences))
+	}
+
+	id := created.ID.String()
+	moved, cancelled := created.Occurrences[1].OccurrenceID, created.Occurrences[2].OccurrenceID
+
+	if err := client.UpdateOccurrence(ctx, 1, id, moved, &zoom.MeetingRequest{StartTime: "2025-03-11T03:00:00Z", Duration: 45}); err != nil {
+		t.Fatalf("UpdateOccurrence: %v", err)
+	}
+	if err := client.DeleteOccurrence(ctx, 1, id, cancelled); err != nil {
+		t.Fatalf("DeleteOccurrence: %v", err)
+	}
+
+	fetched, err := client.GetMeeting(ctx, 1, id)
+	if err != nil {
+		t.Fatalf("GetMeeting: %v", err)
+	}
+	if len(fetched.Occurrences) != 2 {
+		t.Fatalf("occurrences after delete = %+v, want 2", fetched.Occurrences)
+	}
+	got := fetched.Occurrences[1]
+	if got.OccurrenceID != moved || got.Duration != 45 || !got.StartTime.Equal(time.Date(2025, 3, 11, 3, 0, 0, 0, time.UTC)) {
+		t.Fatalf("occurrence not moved: %+v", got)
+	}
+
+	if err := client.DeleteOccurrence(ctx, 1, id, cancelled); !zoom.IsNotFound(err) {
+		t.Fatalf("deleting a cancelled occurrence should be 404, got %v", err)
+	}
+}
+
+func TestPatchMeetingSendsOnlyGivenFields(t *testing.T) {
+	_, client := newTestClient(t)
+	ctx := context.Background()
+
+	created, err := client.CreateMeeting(ctx, 1, &zoom.MeetingRequest{
+		Topic:     "Planning",
+		Type:      zoom.MeetingTypeScheduled,
+		StartTime: "2025-03-03T02:00:00Z",
+		Duration:  60,
+		Agenda:    "Roadmap",
+	})
+	if err != nil {
+		t.Fatalf("CreateMeeting: %v", err)
+	}
+
+	id := created.ID.String()
+	if err := client.PatchMeeting(ctx, 1, id, map[string]interface{}{"topic": "Planning Q2", "agenda": ""}); err != nil {
+		t.Fatalf("PatchMeeting: %v", err)
+	}
+
+	fetched, err := client.GetMeeting(ctx, 1, id)
+	if err != nil {
+		t.Fatalf("GetMeeting: %v", err)
+	}
+	if fetched.Topic != "Planning Q2" || fetched.Agenda != "" {
+		t.Fatalf("topic/agenda = %q/%q, want the patched values", fetched.Topic, fetched.Agenda)
+	}
+	if fetched.Duration != 60 || !fetched.StartTime.Equal(time.Date(2025, 3, 3, 2, 0, 0, 0, time.UTC)) {
+		t.Fatalf("fields left out of the patch changed: %+v", fetched)
+	}
+}
+
+func TestListMeetingsPagination(t *testing.T) {
+	srv, client := newTestClient(t)
+	ctx := context.Background()
+
+	past := time.Now().Add(-48 * time.Hour).UTC().Format(time.RFC3339)
+	future := time.Now().Add(48 * time.Hour).UTC().Format(time.RFC3339)
+	for i := 0; i < 305; i++ {
+		startTime := future
+		if i%5 == 0 {
+			startTime = past
+		}
+		srv.PutMeeting(srv.DefaultUser().ID, map[string]interface{}{"topic": "Meeting", "start_time": startTime})
+	}
+
+	meetings, err := client.ListMeetings(ctx, 1, zoom.ListScheduled)
+	if err != nil {
+		t.Fatalf("ListMeetings: %v", err)
+	}
+	if len(meetings) != 305 {
+		t.Fatalf("listed %d meetings, want 305", len(meetings))
+	}
+
+	pages := 0
+	for _, req := range srv.Requests() {
+		if req.Path == "/v2/users/me/meetings" {
+			pages++
+		}
+	}
+	if pages != 2 {
+		t.Fatalf("fetched %d pages, want 2", pages)
+	}
+
+	previous, err := client.ListMeetings(ctx, 1, zoom.ListPreviousMeetings)
+	if err != nil {
+		t.Fatalf("ListMeetings previous: %v", err)
+	}
+	if len(previous) != 61 {
+		t.Fatalf("listed %d previous meetings, want 61", len(previous))
+	}
+}
@@ -0,0 +1,268 @@
+package zoom
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Zoom meeting types
+const (
+	MeetingTypeInstant        = 1
+	MeetingTypeScheduled      = 2
+	MeetingTypeRecurringFixed = 8 // Recurring meeting with a fixed time
+)
+
+// Auto recording modes
+const (
+	AutoRecordingLocal = "local"
+	AutoRecordingCloud = "cloud"
+	AutoRecordingNone  = "none"
+)
+
+// Registration approval types
+const (
+	ApprovalAutomatic      = 0
+	ApprovalManual         = 1
+	ApprovalNoRegistration = 2
+)
+
+// Meeting list types accepted by ListMeetings
+const (
+	ListScheduled        = "scheduled" // Unexpired previous, live and upcoming meetings
+	ListLive             = "live"
+	ListUpcoming         = "upcoming"
+	ListUpcomingMeetings = "upcoming_meetings"
+	ListPreviousMeetings = "previous_meetings"
+)
+
+// ValidListType reports whether t is one of Zoom's meeting list types
+func ValidListType(t string) bool {
+	switch t {
+	case ListScheduled, ListLive, ListUpcoming, ListUpcomingMeetings, ListPreviousMeetings:
+		return true
+	}
+	return false
+}
+
+const (
+	maxPageSize  = 300 // Largest page_size Zoom accepts
+	maxListPages = 100 // Guards against a list that never ends
+)
+
+// Recurrence types
+const (
+	RecurrenceDaily   = 1
+	RecurrenceWeekly  = 2
+	RecurrenceMonthly = 3
+)
+
+// MeetingID is the numeric ID of a Zoom meeting
+type MeetingID int64
+
+func (id MeetingID) String() string {
+	return strconv.FormatInt(int64(id), 10)
+}
+
+// Meeting is a meeting as returned by the Zoom API
+type Meeting struct {
+	ID        MeetingID `json:"id"`
+	UUID      string    `json:"uuid"`
+	HostID    string    `json:"host_id"`
+	Topic     string    `json:"topic"`
+	Type      int       `json:"type"`
+	StartTime time.Time `json:"start_time"`
+	Duration  int       `json:"duration"`
+	Timezone  string    `json:"timezone"`
+	Agenda    string    `json:"agenda"`
+	Password  string    `json:"password"`
+	JoinURL   string    `json:"join_url"`
+	StartURL  string    `json:"start_url"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+
+	Settings *MeetingSettings `json:"settings"`
+
+	// Only set for recurring meetings
+	Recurrence  *Recurrence  `json:"recurrence"`
+	Occurrences []Occurrence `json:"occurrences"`
+}
+
+// Recurrence is the repeat rule of a recurring meeting. Weekdays are
+// numbered from 1 (Sunday) to 7 (Saturday); WeeklyDays is a comma-separated
+// list of them. Only one of EndTimes and EndDateTime may be set.
+type Recurrence struct {
+	Type           int        `json:"type"`
+	RepeatInterval int        `json:"repeat_interval,omitempty"`
+	WeeklyDays     string     `json:"weekly_days,omitempty"`
+	MonthlyDay     int        `json:"monthly_day,omitempty"`
+	MonthlyWeek    int        `json:"monthly_week,omitempty"`
+	MonthlyWeekDay int        `json:"monthly_week_day,omitempty"`
+	EndTimes       int        `json:"end_times,omitempty"`
+	EndDateTime    *time.Time `json:"end_date_time,omitempty"`
+}
+
+// MeetingSettings holds the meeting options Zoom keeps under "settings".
+// Nil fields are left out of requests so updates only change what is set.
+// AlternativeHosts is a semicolon-separated list of email addresses.
+type MeetingSettings struct {
+	HostVideo        *bool   `json:"host_video,omitempty"`
+	ParticipantVideo *bool   `json:"participant_video,omitempty"`
+	JoinBeforeHost   *bool   `json:"join_before_host,omitempty"`
+	MuteUponEntry    *bool   `json:"mute_upon_entry,omitempty"`
+	WaitingRoom      *bool   `json:"waiting_room,omitempty"`
+	AutoRecording    string  `json:"auto_recording,omitempty"`
+	AlternativeHosts *string `json:"alternative_hosts,omitempty"`
+	ApprovalType     *int    `json:"approval_type,omitempty"`
+
+	MeetingInvitees []Invitee `json:"meeting_invitees,omitempty"`
+}
+
+// Invitee is a person Zoom sends the meeting invitation to
+type Invitee struct {
+	Email string `json:"email"`
+}
+
+// Occurrence is a single instance of a recurring meeting
+type Occurrence struct {
+	OccurrenceID string    `json:"occurrence_id"`
+	StartTime    time.Time `json:"start_time"`
+	Duration     int       `json:"duration"`
+	Status       string    `json:"status"`
+}
+
+// UnmarshalJSON tolerates empty start_time and created_at values from Zoom
+func (m *Meeting) UnmarshalJSON(data []byte) error {
+	type alias Meeting
+	aux := struct {
+		*alias
+		StartTime string `json:"start_time"`
+		CreatedAt string `json:"created_at"`
+	}{alias: (*alias)(m)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	var err error
+	if m.StartTime, err = parseTime(aux.StartTime); err != nil {
+		return fmt.Errorf("invalid start_time: %w", err)
+	}
+	if m.CreatedAt, err = parseTime(aux.CreatedAt); err != nil {
+		return fmt.Errorf("invalid created_at: %w", err)
+	}
+	return nil
+}
+
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// MeetingRequest is the payload used to create or update a meeting
+type MeetingRequest struct {
+	Topic     string `json:"topic,omitempty"`
+	Type      int    `json:"type,omitempty"`
+	StartTime string `json:"start_time,omitempty"`
+	Duration  int    `json:"duration,omitempty"`
+	Timezone  string `json:"timezone,omitempty"`
+	Agenda    string `json:"agenda,omitempty"`
+	Password  string `json:"password,omitempty"`
+
+	Recurrence *Recurrence      `json:"recurrence,omitempty"`
+	Settings   *MeetingSettings `json:"settings,omitempty"`
+}
+
+// MeetingList is a single page of listed meetings
+type MeetingList struct {
+	PageSize      int       `json:"page_size"`
+	TotalRecords  int       `json:"total_records"`
+	NextPageToken string    `json:"next_page_token"`
+	Meetings      []Meeting `json:"meetings"`
+}
+
+// ListMeetings fetches every meeting of the given list type, following
+// next_page_token through all pages. An empty listType uses Zoom's default,
+// ListScheduled.
+func (c *Client) ListMeetings(ctx context.Context, userID uint, listType string) ([]Meeting, error) {
+	query := url.Values{"page_size": {strconv.Itoa(maxPageSize)}}
+	if listType != "" {
+		query.Set("type", listType)
+	}
+
+	var meetings []Meeting
+	for page := 0; page < maxListPages; page++ {
+		var result MeetingList
+		if err := c.do(ctx, userID, http.MethodGet, "/users/me/meetings?"+query.Encode(), nil, http.StatusOK, &result); err != nil {
+			return nil, err
+		}
+		meetings = append(meetings, result.Meetings...)
+
+		if result.NextPageToken == "" {
+			return meetings, nil
+		}
+		query.Set("next_page_token", result.NextPageToken)
+	}
+	return nil, fmt.Errorf("zoom: meeting list has more than %d pages", maxListPages)
+}
+
+// GetMeeting fetches a single meeting by ID
+func (c *Client) GetMeeting(ctx context.Context, userID uint, meetingID string) (*Meeting, error) {
+	var meeting Meeting
+	if err := c.do(ctx, userID, http.MethodGet, meetingPath(meetingID), nil, http.StatusOK, &meeting); err != nil {
+		return nil, err
+	}
+	return &meeting, nil
+}
+
+// CreateMeeting creates a meeting hosted by the given user
+func (c *Client) CreateMeeting(ctx context.Context, userID uint, req *MeetingRequest) (*Meeting, error) {
+	var meeting Meeting
+	if err := c.do(ctx, userID, http.MethodPost, "/users/me/meetings", req, http.StatusCreated, &meeting); err != nil {
+		return nil, err
+	}
+	return &meeting, nil
+}
+
+// UpdateMeeting updates a meeting. Zoom replies 204 with no body.
+func (c *Client) UpdateMeeting(ctx context.Context, userID uint, meetingID string, req *MeetingRequest) error {
+	return c.do(ctx, userID, http.MethodPatch, meetingPath(meetingID), req, http.StatusNoContent, nil)
+}
+
+// PatchMeeting updates exactly the given fields of a meeting, including
+// empty values that UpdateMeeting would leave out
+func (c *Client) PatchMeeting(ctx context.Context, userID uint, meetingID string, fields map[string]interface{}) error {
+	return c.do(ctx, userID, http.MethodPatch, meetingPath(meetingID), fields, http.StatusNoContent, nil)
+}
+
+// DeleteMeeting deletes a meeting
+func (c *Client) DeleteMeeting(ctx context.Context, userID uint, meetingID string) error {
+	return c.do(ctx, userID, http.MethodDelete, meetingPath(meetingID), nil, http.StatusNoContent, nil)
+}
+
+// UpdateOccurrence updates a single occurrence of a recurring meeting. Zoom
+// only applies start_time, duration and agenda to an occurrence.
+func (c *Client) UpdateOccurrence(ctx context.Context, userID uint, meetingID, occurrenceID string, req *MeetingRequest) error {
+	return c.do(ctx, userID, http.MethodPatch, occurrencePath(meetingID, occurrenceID), req, http.StatusNoContent, nil)
+}
+
+// DeleteOccurrence cancels a single occurrence of a recurring meeting
+func (c *Client) DeleteOccurrence(ctx context.Context, userID uint, meetingID, occurrenceID string) error {
+	return c.do(ctx, userID, http.MethodDelete, occurrencePath(meetingID, occurrenceID), nil, http.StatusNoContent, nil)
+}
+
+// meetingPath returns the API path of a meeting. The ID is escaped, as it
+// may come from a request and must not change the path or add a query.
+func meetingPath(meetingID string) string {
+	return "/meetings/" + url.PathEscape(meetingID)
+}
+
+func occurrencePath(meetingID, occurrenceID string) string {
+	return meetingPath(meetingID) + "?occurrence_id=" + url.QueryEscape(occurrenceID)
+}
@@ -0,0 +1,104 @@
+package zoom
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultOAuthURL is the base URL of Zoom's OAuth endpoints
+const DefaultOAuthURL = "https://zoom.us"
+
+// OAuth holds the credentials of the Zoom OAuth app
+type OAuth struct {
+	BaseURL      string
+	ClientID     string
+	ClientSecret string
+	RedirectURI  string
+	HTTPClient   *http.Client
+}
+
+// Token is the response of Zoom's OAuth token endpoint
+type Token struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+	Scope        string `json:"scope"`
+	AccountID    string `json:"account_id"`
+}
+
+// Expiry returns the moment the access token expires, counted from now
+func (t *Token) Expiry() time.Time {
+	return time.Now().Add(time.Duration(t.ExpiresIn) * time.Second)
+}
+
+// AuthCodeURL returns the URL the user is sent to in order to authorize the app
+func (o *OAuth) AuthCodeURL() string {
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", o.ClientID)
+	params.Set("redirect_uri", o.RedirectURI)
+	return o.BaseURL + "/oauth/authorize?" + params.Encode()
+}
+
+// Exchange trades an authorization code for a token
+func (o *OAuth) Exchange(ctx context.Context, code string) (*Token, error) {
+	data := url.Values{}
+	data.Set("grant_type", "authorization_code")
+	data.Set("code", code)
+	data.Set("redirect_uri", o.RedirectURI)
+	return o.requestToken(ctx, data)
+}
+
+// Refresh trades a refresh token for a new token. Zoom rotates the refresh
+// token on every call, so the returned RefreshToken must be stored.
+func (o *OAuth) Refresh(ctx context.Context, refreshToken string) (*Token, error) {
+	data := url.Values{}
+	data.Set("grant_type", "refresh_token")
+	data.Set("refresh_token", refreshToken)
+	return o.requestToken(ctx, data)
+}
+
+func (o *OAuth) requestToken(ctx context.Context, data url.Values) (*Token, error) {
+	if o.ClientID == "" || o.ClientSecret == "" {
+		return nil, fmt.Errorf("missing Zoom API credentials. Please check your environment variables")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.BaseURL+"/oauth/token", strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(o.ClientID, o.ClientSecret)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	httpClient := o.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp.StatusCode, body)
+	}
+
+	var token Token
+	if err := json.Unmarshal(body, &token); err != nil {
+		return nil, fmt.Errorf("zoom: invalid token response: %w", err)
+	}
+	return &token, nil
+}
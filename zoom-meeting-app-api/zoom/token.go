@@ -0,0 +1,103 @@
+package zoom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+	"zoom-meeting-app/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrNotConnected is returned when a user has not connected a Zoom account yet
+var ErrNotConnected = errors.New("zoom: user has not connected a Zoom account")
+
+// expiryLeeway refreshes access tokens slightly before Zoom expires them
+const expiryLeeway = time.Minute
+
+// TokenSource provides the OAuth access token used to call the Zoom API on
+// behalf of an application user
+type TokenSource interface {
+	Token(ctx context.Context, userID uint) (string, error)
+}
+
+// StaticToken is a TokenSource that always returns the same access token,
+// e.g. a freshly exchanged token that has not been stored yet
+type StaticToken string
+
+func (t StaticToken) Token(ctx context.Context, userID uint) (string, error) {
+	return string(t), nil
+}
+
+// UserTokenSource serves the Zoom tokens stored on models.User, refreshing
+// and persisting them when they expire
+type UserTokenSource struct {
+	DB    *gorm.DB
+	OAuth *OAuth
+
+	mu    sync.Mutex
+	locks map[uint]*sync.Mutex
+}
+
+// NewUserTokenSource creates a TokenSource backed by the users table
+func NewUserTokenSource(db *gorm.DB, oauth *OAuth) *UserTokenSource {
+	return &UserTokenSource{DB: db, OAuth: oauth}
+}
+
+// userLock serializes refreshes per user, because Zoom invalidates the old
+// refresh token as soon as it has been used once
+func (s *UserTokenSource) userLock(userID uint) *sync.Mutex {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.locks == nil {
+		s.locks = make(map[uint]*sync.Mutex)
+	}
+	lock, ok := s.locks[userID]
+	if !ok {
+		lock = &sync.Mutex{}
+		s.locks[userID] = lock
+	}
+	return lock
+}
+
+func (s *UserTokenSource) Token(ctx context.Context, userID uint) (string, error) {
+	lock := s.userLock(userID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	var user models.User
+	if err := s.DB.WithContext(ctx).First(&user, userID).Error; err != nil {
+		return "", fmt.Errorf("user not found")
+	}
+
+	// Use the stored access token while it is still valid
+	if user.ZoomToken != "" && time.Now().Add(expiryLeeway).Before(user.ZoomExpires) {
+		return user.ZoomToken, nil
+	}
+
+	if user.ZoomRefresh == "" {
+		return "", ErrNotConnected
+	}
+
+	token, err := s.OAuth.Refresh(ctx, user.ZoomRefresh)
+	if err != nil {
+		return "", fmt.Errorf("failed to refresh Zoom token: %w", err)
+	}
+
+	updates := map[string]interface{}{
+		"zoom_token":   token.AccessToken,
+		"zoom_refresh": token.RefreshToken,
+		"zoom_expires": token.Expiry(),
+	}
+	if token.AccountID != "" {
+		updates["zoom_account"] = token.AccountID
+	}
+	if err := s.DB.WithContext(ctx).Model(&user).Updates(updates).Error; err != nil {
+		return "", fmt.Errorf("failed to update user with Zoom credentials")
+	}
+
+	return token.AccessToken, nil
+}
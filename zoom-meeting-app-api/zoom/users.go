@@ -0,0 +1,25 @@
+package zoom
+
+import (
+	"context"
+	"net/http"
+)
+
+// User is the Zoom profile of the user owning the token
+type User struct {
+	ID        string `json:"id"`
+	Email     string `json:"email"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	AccountID string `json:"account_id"`
+	Timezone  string `json:"timezone"`
+}
+
+// Me fetches the Zoom profile of the given user
+func (c *Client) Me(ctx context.Context, userID uint) (*User, error) {
+	var user User
+	if err := c.do(ctx, userID, http.MethodGet, "/users/me", nil, http.StatusOK, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
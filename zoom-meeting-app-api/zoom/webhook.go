@@ -0,0 +1,102 @@
+package zoom
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Webhook event types handled by the app
+const (
+	EventURLValidation  = "endpoint.url_validation"
+	EventMeetingCreated = "meeting.created"
+	EventMeetingUpdated = "meeting.updated"
+	EventMeetingDeleted = "meeting.deleted"
+	EventMeetingStarted = "meeting.started"
+	EventMeetingEnded   = "meeting.ended"
+)
+
+// WebhookTolerance is how far the request timestamp of a webhook may be from
+// the current time, to limit replays of captured requests
+const WebhookTolerance = 5 * time.Minute
+
+// Errors returned by VerifyWebhook
+var (
+	ErrWebhookSignature = errors.New("zoom: invalid webhook signature")
+	ErrWebhookTimestamp = errors.New("zoom: webhook timestamp outside tolerance")
+)
+
+// WebhookEvent is the envelope of a Zoom webhook notification
+type WebhookEvent struct {
+	Event   string          `json:"event"`
+	EventTS int64           `json:"event_ts"` // Unix milliseconds
+	Payload json.RawMessage `json:"payload"`
+}
+
+// URLValidationPayload is the payload of an endpoint.url_validation event
+type URLValidationPayload struct {
+	PlainToken string `json:"plainToken"`
+}
+
+// URLValidationResponse is the reply Zoom expects to a validation challenge
+type URLValidationResponse struct {
+	PlainToken     string `json:"plainToken"`
+	EncryptedToken string `json:"encryptedToken"`
+}
+
+// MeetingEventPayload is the payload of the meeting.* events. For
+// meeting.updated, Object only holds the changed fields and the ID.
+type MeetingEventPayload struct {
+	AccountID string       `json:"account_id"`
+	Object    MeetingEvent `json:"object"`
+}
+
+// MeetingEvent is the meeting a webhook event is about. Occurrences is set
+// when the event only concerns some occurrences of a recurring meeting.
+type MeetingEvent struct {
+	ID          MeetingID    `json:"id"`
+	UUID        string       `json:"uuid"`
+	HostID      string       `json:"host_id"`
+	Topic       string       `json:"topic"`
+	Occurrences []Occurrence `json:"occurrences"`
+}
+
+// NewURLValidationResponse answers Zoom's endpoint validation challenge
+func NewURLValidationResponse(secret, plainToken string) URLValidationResponse {
+	return URLValidationResponse{
+		PlainToken:     plainToken,
+		EncryptedToken: hex.EncodeToString(webhookMAC(secret, plainToken)),
+	}
+}
+
+// VerifyWebhook checks the x-zm-signature header of a webhook request, an
+// HMAC-SHA256 of "v0:{x-zm-request-timestamp}:{body}" keyed with the secret
+// token, and that the timestamp is within WebhookTolerance of now
+func VerifyWebhook(secret string, header http.Header, body []byte, now time.Time) error {
+	timestamp := header.Get("x-zm-request-timestamp")
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrWebhookSignature
+	}
+
+	expected := "v0=" + hex.EncodeToString(webhookMAC(secret, "v0:"+timestamp+":"+string(body)))
+	if !hmac.Equal([]byte(header.Get("x-zm-signature")), []byte(expected)) {
+		return ErrWebhookSignature
+	}
+
+	if skew := now.Sub(time.Unix(seconds, 0)); skew > WebhookTolerance || skew < -WebhookTolerance {
+		return ErrWebhookTimestamp
+	}
+	return nil
+}
+
+func webhookMAC(secret, message string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(message))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,32 @@
+package zoom_test
+
+import (
+	"io"
+	"testing"
+	"time"
+	"zoom-meeting-app/zoom"
+	"zoom-meeting-app/zoom/zoomtest"
+)
+
+func TestVerifyWebhook(t *testing.T) {
+	req, err := zoomtest.NewWebhookRequest("http://localhost/webhooks/zoom", "secret", zoom.EventMeetingStarted, map[string]interface{}{
+		"object": map[string]interface{}{"id": 85000000001},
+	})
+	if err != nil {
+		t.Fatalf("NewWebhookRequest: %v", err)
+	}
+	body, _ := io.ReadAll(req.Body)
+
+	if err := zoom.VerifyWebhook("secret", req.Header, body, time.Now()); err != nil {
+		t.Fatalf("valid webhook rejected: %v", err)
+	}
+	if err := zoom.VerifyWebhook("other", req.Header, body, time.Now()); err != zoom.ErrWebhookSignature {
+		t.Fatalf("wrong secret: got %v, want ErrWebhookSignature", err)
+	}
+	if err := zoom.VerifyWebhook("secret", req.Header, append(body, ' '), time.Now()); err != zoom.ErrWebhookSignature {
+		t.Fatalf("tampered body: got %v, want ErrWebhookSignature", err)
+	}
+	if err := zoom.VerifyWebhook("secret", req.Header, body, time.Now().Add(10*time.Minute)); err != zoom.ErrWebhookTimestamp {
+		t.Fatalf("stale timestamp: got %v, want ErrWebhookTimestamp", err)
+	}
+}
@@ -0,0 +1,713 @@
+// Package zoomtest provides an in-memory fake of the Zoom OAuth and REST APIs
+// for integration tests and offline development.
+package zoomtest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"zoom-meeting-app/zoom"
+)
+
+// Default OAuth app credentials accepted by a new Fake
+const (
+	DefaultClientID     = "fake-client-id"
+	DefaultClientSecret = "fake-client-secret"
+)
+
+// Failure describes an error the fake returns instead of handling a request
+type Failure struct {
+	Method  string // HTTP method to match, empty matches any
+	Path    string // URL path prefix to match, empty matches any
+	Status  int
+	Code    int    // Zoom error code placed in the body
+	Message string // Zoom error message placed in the body
+	Times   int    // number of requests to fail, 0 fails until ClearFailures
+}
+
+// Request is a request received by the fake
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+}
+
+type tokenInfo struct {
+	userID  string
+	expires time.Time
+}
+
+// occurrenceOverride records the changes made to a single occurrence
+type occurrenceOverride struct {
+	startTime time.Time
+	duration  int
+	deleted   bool
+}
+
+type authCode struct {
+	userID      string
+	redirectURI string
+}
+
+// Fake is an in-memory implementation of the parts of the Zoom API used by
+// the app: OAuth authorize/token/refresh, /users/me and meeting CRUD
+type Fake struct {
+	ClientID     string
+	ClientSecret string
+	TokenTTL     time.Duration
+
+	mu            sync.Mutex
+	mux           *http.ServeMux
+	users         map[string]*zoom.User
+	defaultUserID string
+	codes         map[string]authCode
+	accessTokens  map[string]tokenInfo
+	refreshTokens map[string]string
+	meetings      map[string]map[string]interface{}
+	overrides     map[string]map[string]occurrenceOverride
+	nextMeetingID int64
+	failures      []*Failure
+	requests      []Request
+}
+
+// NewFake creates a Fake with a single default Zoom user
+func NewFake() *Fake {
+	f := &Fake{
+		ClientID:      DefaultClientID,
+		ClientSecret:  DefaultClientSecret,
+		TokenTTL:      time.Hour,
+		users:         make(map[string]*zoom.User),
+		codes:         make(map[string]authCode),
+		accessTokens:  make(map[string]tokenInfo),
+		refreshTokens: make(map[string]string),
+		meetings:      make(map[string]map[string]interface{}),
+		overrides:     make(map[string]map[string]occurrenceOverride),
+		nextMeetingID: 85000000000,
+	}
+	f.defaultUserID = f.AddUser("user@local.com").ID
+
+	f.mux = http.NewServeMux()
+	f.mux.HandleFunc("GET /oauth/authorize", f.handleAuthorize)
+	f.mux.HandleFunc("POST /oauth/token", f.handleToken)
+	f.mux.HandleFunc("GET /v2/users/me", f.authenticated(f.handleMe))
+	f.mux.HandleFunc("GET /v2/users/me/meetings", f.authenticated(f.handleListMeetings))
+	f.mux.HandleFunc("POST /v2/users/me/meetings", f.authenticated(f.handleCreateMeeting))
+	f.mux.HandleFunc("GET /v2/meetings/{id}", f.authenticated(f.handleGetMeeting))
+	f.mux.HandleFunc("PATCH /v2/meetings/{id}", f.authenticated(f.handleUpdateMeeting))
+	f.mux.HandleFunc("DELETE /v2/meetings/{id}", f.authenticated(f.handleDeleteMeeting))
+	return f
+}
+
+// AddUser registers a Zoom user and returns its profile
+func (f *Fake) AddUser(email string) *zoom.User {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	user := &zoom.User{
+		ID:        "zoom-" + randomString(8),
+		Email:     email,
+		AccountID: "account-" + randomString(8),
+		Timezone:  "UTC",
+	}
+	f.users[user.ID] = user
+	return user
+}
+
+// DefaultUser returns the Zoom user that authorizes the app when no other user is requested
+func (f *Fake) DefaultUser() *zoom.User {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.users[f.defaultUserID]
+}
+
+// IssueToken returns a fresh token for the Zoom user, skipping the authorize flow
+func (f *Fake) IssueToken(zoomUserID string) *zoom.Token {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.issueTokenLocked(zoomUserID)
+}
+
+func (f *Fake) issueTokenLocked(zoomUserID string) *zoom.Token {
+	token := &zoom.Token{
+		AccessToken:  "access-" + randomString(16),
+		RefreshToken: "refresh-" + randomString(16),
+		ExpiresIn:    int(f.TokenTTL.Seconds()),
+		Scope:        "meeting:read meeting:write user:read",
+		AccountID:    f.users[zoomUserID].AccountID,
+	}
+	f.accessTokens[token.AccessToken] = tokenInfo{userID: zoomUserID, expires: time.Now().Add(f.TokenTTL)}
+	f.refreshTokens[token.RefreshToken] = zoomUserID
+	return token
+}
+
+// Fail makes the fake answer matching requests with the given error
+func (f *Fake) Fail(failure Failure) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failures = append(f.failures, &failure)
+}
+
+// ClearFailures removes all injected failures
+func (f *Fake) ClearFailures() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failures = nil
+}
+
+// Requests returns every request received so far
+func (f *Fake) Requests() []Request {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Request(nil), f.requests...)
+}
+
+// Meeting returns the stored meeting with the given ID
+func (f *Fake) Meeting(id string) (*zoom.Meeting, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	stored, ok := f.meetings[id]
+	if !ok {
+		return nil, false
+	}
+	return toMeeting(stored), true
+}
+
+// Meetings returns all stored meetings ordered by ID
+func (f *Fake) Meetings() []zoom.Meeting {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	meetings := make([]zoom.Meeting, 0, len(f.meetings))
+	for _, id := range f.sortedMeetingIDs("") {
+		meetings = append(meetings, *toMeeting(f.meetings[id]))
+	}
+	return meetings
+}
+
+// PutMeeting stores a meeting for the Zoom user directly, as if it had been
+// created from the Zoom client, and returns its ID
+func (f *Fake) PutMeeting(zoomUserID string, meeting map[string]interface{}) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.createMeetingLocked(zoomUserID, meeting)
+}
+
+// RemoveMeeting deletes a meeting directly, as if it had been deleted from the Zoom client
+func (f *Fake) RemoveMeeting(id string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.meetings, id)
+	delete(f.overrides, id)
+}
+
+func (f *Fake) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	f.requests = append(f.requests, Request{Method: r.Method, Path: r.URL.Path, Query: r.URL.Query()})
+	failure := f.matchFailureLocked(r)
+	f.mu.Unlock()
+
+	if failure != nil {
+		writeError(w, failure.Status, failure.Code, failure.Message)
+		return
+	}
+	f.mux.ServeHTTP(w, r)
+}
+
+func (f *Fake) matchFailureLocked(r *http.Request) *Failure {
+	for i, failure := range f.failures {
+		if failure.Method != "" && failure.Method != r.Method {
+			continue
+		}
+		if !strings.HasPrefix(r.URL.Path, failure.Path) {
+			continue
+		}
+		if failure.Times > 0 {
+			failure.Times--
+			if failure.Times == 0 {
+				f.failures = append(f.failures[:i], f.failures[i+1:]...)
+			}
+		}
+		return failure
+	}
+	return nil
+}
+
+// Server is a Fake served over a local httptest server
+type Server struct {
+	*Fake
+	*httptest.Server
+}
+
+// NewServer starts a Server. Callers must Close it when done.
+func NewServer() *Server {
+	fake := NewFake()
+	return &Server{Fake: fake, Server: httptest.NewServer(fake)}
+}
+
+// OAuthURL returns the base URL to use as zoom.OAuth.BaseURL
+func (s *Server) OAuthURL() string {
+	return s.URL
+}
+
+// APIURL returns the base URL to use as zoom.Client.BaseURL
+func (s *Server) APIURL() string {
+	return s.URL + "/v2"
+}
+
+// OAuth returns a zoom.OAuth configured for this server
+func (s *Server) OAuth(redirectURI string) *zoom.OAuth {
+	return &zoom.OAuth{
+		BaseURL:      s.OAuthURL(),
+		ClientID:     s.ClientID,
+		ClientSecret: s.ClientSecret,
+		RedirectURI:  redirectURI,
+		HTTPClient:   s.Client(),
+	}
+}
+
+// NewClient returns a zoom.Client talking to this server
+func (s *Server) NewClient(tokens zoom.TokenSource) *zoom.Client {
+	client := zoom.NewClient(tokens)
+	client.BaseURL = s.APIURL()
+	client.HTTPClient = s.Client()
+	return client
+}
+
+func (f *Fake) handleAuthorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("response_type") != "code" || query.Get("client_id") != f.ClientID {
+		writeError(w, http.StatusBadRequest, 4700, "Invalid client_id or response_type")
+		return
+	}
+	redirectURI, err := url.Parse(query.Get("redirect_uri"))
+	if err != nil || redirectURI.String() == "" {
+		writeError(w, http.StatusBadRequest, 4700, "Invalid redirect_uri")
+		return
+	}
+
+	f.mu.Lock()
+	userID := f.defaultUserID
+	if requested := query.Get("user_id"); requested != "" {
+		if _, ok := f.users[requested]; !ok {
+			f.mu.Unlock()
+			writeError(w, http.StatusBadRequest, 1001, "User does not exist: "+requested)
+			return
+		}
+		userID = requested
+	}
+	code := "code-" + randomString(16)
+	f.codes[code] = authCode{userID: userID, redirectURI: redirectURI.String()}
+	f.mu.Unlock()
+
+	params := redirectURI.Query()
+	params.Set("code", code)
+	if state := query.Get("state"); state != "" {
+		params.Set("state", state)
+	}
+	redirectURI.RawQuery = params.Encode()
+	http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+}
+
+func (f *Fake) handleToken(w http.ResponseWriter, r *http.Request) {
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok || clientID != f.ClientID || clientSecret != f.ClientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"reason": "Invalid client_id or client_secret", "error": "invalid_client"})
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"reason": err.Error(), "error": "invalid_request"})
+		return
+	}
+	// Zoom accepts the parameters both in the body and in the query string
+	params := r.Form
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch params.Get("grant_type") {
+	case "authorization_code":
+		code, ok := f.codes[params.Get("code")]
+		if !ok {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"reason": "Invalid authorization code", "error": "invalid_grant"})
+			return
+		}
+		if code.redirectURI != params.Get("redirect_uri") {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"reason": "Redirect URI mismatch.", "error": "invalid_request"})
+			return
+		}
+		delete(f.codes, params.Get("code"))
+		writeJSON(w, http.StatusOK, f.issueTokenLocked(code.userID))
+	case "refresh_token":
+		userID, ok := f.refreshTokens[params.Get("refresh_token")]
+		if !ok {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"reason": "Invalid Token!", "error": "invalid_request"})
+			return
+		}
+		// Refresh tokens are single use
+		delete(f.refreshTokens, params.Get("refresh_token"))
+		writeJSON(w, http.StatusOK, f.issueTokenLocked(userID))
+	default:
+		writeJSON(w, http.StatusBadRequest, map[string]string{"reason": "Unsupported grant type", "error": "unsupported_grant_type"})
+	}
+}
+
+// authenticated resolves the bearer token to a Zoom user ID before calling next
+func (f *Fake) authenticated(next func(w http.ResponseWriter, r *http.Request, userID string)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		accessToken := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+		f.mu.Lock()
+		info, ok := f.accessTokens[accessToken]
+		f.mu.Unlock()
+
+		if !ok {
+			writeError(w, http.StatusUnauthorized, 124, "Invalid access token.")
+			return
+		}
+		if time.Now().After(info.expires) {
+			writeError(w, http.StatusUnauthorized, 124, "Access token is expired.")
+			return
+		}
+		next(w, r, info.userID)
+	}
+}
+
+func (f *Fake) handleMe(w http.ResponseWriter, r *http.Request, userID string) {
+	f.mu.Lock()
+	user := *f.users[userID]
+	f.mu.Unlock()
+	writeJSON(w, http.StatusOK, user)
+}
+
+func (f *Fake) handleListMeetings(w http.ResponseWriter, r *http.Request, userID string) {
+	listType := r.URL.Query().Get("type")
+	if listType == "" {
+		listType = zoom.ListScheduled
+	}
+	if !zoom.ValidListType(listType) {
+		writeError(w, http.StatusBadRequest, 300, "Invalid field: type")
+		return
+	}
+
+	pageSize := 30
+	if value := r.URL.Query().Get("page_size"); value != "" {
+		size, err := strconv.Atoi(value)
+		if err != nil || size < 1 {
+			writeError(w, http.StatusBadRequest, 300, "Invalid page_size")
+			return
+		}
+		pageSize = min(size, 300)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var ids []string
+	now := time.Now()
+	for _, id := range f.sortedMeetingIDs(userID) {
+		if matchesListType(f.meetings[id], listType, now) {
+			ids = append(ids, id)
+		}
+	}
+
+	start := 0
+	if token := r.URL.Query().Get("next_page_token"); token != "" {
+		offset, err := strconv.Atoi(token)
+		if err != nil || offset < 0 || offset > len(ids) {
+			writeError(w, http.StatusBadRequest, 300, "Invalid next_page_token")
+			return
+		}
+		start = offset
+	}
+	end := min(start+pageSize, len(ids))
+
+	meetings := make([]map[string]interface{}, 0, end-start)
+	for _, id := range ids[start:end] {
+		meetings = append(meetings, f.meetings[id])
+	}
+
+	nextPageToken := ""
+	if end < len(ids) {
+		nextPageToken = strconv.Itoa(end)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"page_size":       pageSize,
+		"total_records":   len(ids),
+		"next_page_token": nextPageToken,
+		"meetings":        meetings,
+	})
+}
+
+func (f *Fake) handleCreateMeeting(w http.ResponseWriter, r *http.Request, userID string) {
+	var body map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, 300, "Request Body should be a valid JSON object.")
+		return
+	}
+
+	f.mu.Lock()
+	id := f.createMeetingLocked(userID, body)
+	meeting := f.meetings[id]
+	f.mu.Unlock()
+
+	writeJSON(w, http.StatusCreated, meeting)
+}
+
+func (f *Fake) createMeetingLocked(userID string, body map[string]interface{}) string {
+	f.nextMeetingID++
+	id := strconv.FormatInt(f.nextMeetingID, 10)
+
+	meeting := map[string]interface{}{
+		"type":     zoom.MeetingTypeScheduled,
+		"duration": 60,
+		"timezone": "UTC",
+		"settings": map[string]interface{}{
+			"host_video":        false,
+			"participant_video": false,
+			"join_before_host":  false,
+			"mute_upon_entry":   false,
+			"waiting_room":      false,
+			"auto_recording":    zoom.AutoRecordingNone,
+			"alternative_hosts": "",
+			"approval_type":     zoom.ApprovalNoRegistration,
+		},
+	}
+	mergePatch(meeting, body)
+	meeting["id"] = f.nextMeetingID
+	meeting["uuid"] = randomString(12) + "=="
+	meeting["host_id"] = userID
+	meeting["join_url"] = "https://zoom.us/j/" + id
+	meeting["start_url"] = "https://zoom.us/s/" + id
+	meeting["status"] = "waiting"
+	meeting["created_at"] = time.Now().UTC().Format(time.RFC3339)
+	f.setOccurrencesLocked(id, meeting)
+
+	f.meetings[id] = meeting
+	return id
+}
+
+func (f *Fake) handleGetMeeting(w http.ResponseWriter, r *http.Request, userID string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	meeting, ok := f.meetingForUserLocked(w, r.PathValue("id"), userID)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, meeting)
+}
+
+func (f *Fake) handleUpdateMeeting(w http.ResponseWriter, r *http.Request, userID string) {
+	var body map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, 300, "Request Body should be a valid JSON object.")
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	id := r.PathValue("id")
+	meeting, ok := f.meetingForUserLocked(w, id, userID)
+	if !ok {
+		return
+	}
+
+	if occurrenceID := r.URL.Query().Get("occurrence_id"); occurrenceID != "" {
+		if !f.occurrenceExistsLocked(w, id, occurrenceID) {
+			return
+		}
+		override := f.overrides[id][occurrenceID]
+		if value, ok := body["start_time"].(string); ok {
+			startTime, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, 300, "Invalid start_time.")
+				return
+			}
+			override.startTime = startTime.UTC()
+		}
+		if value, ok := body["duration"].(float64); ok {
+			override.duration = int(value)
+		}
+		f.setOverrideLocked(id, occurrenceID, override)
+		f.setOccurrencesLocked(id, meeting)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	// Editing the whole series discards the changes made to single occurrences
+	delete(f.overrides, id)
+	mergePatch(meeting, body)
+	f.setOccurrencesLocked(id, meeting)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (f *Fake) handleDeleteMeeting(w http.ResponseWriter, r *http.Request, userID string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	id := r.PathValue("id")
+	meeting, ok := f.meetingForUserLocked(w, id, userID)
+	if !ok {
+		return
+	}
+
+	if occurrenceID := r.URL.Query().Get("occurrence_id"); occurrenceID != "" {
+		if !f.occurrenceExistsLocked(w, id, occurrenceID) {
+			return
+		}
+		override := f.overrides[id][occurrenceID]
+		override.deleted = true
+		f.setOverrideLocked(id, occurrenceID, override)
+		f.setOccurrencesLocked(id, meeting)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	delete(f.meetings, id)
+	delete(f.overrides, id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// occurrenceExistsLocked reports whether the meeting has a live occurrence
+// with the given ID, writing Zoom's 404 response when it does not
+func (f *Fake) occurrenceExistsLocked(w http.ResponseWriter, id, occurrenceID string) bool {
+	for _, occurrence := range expandOccurrences(toMeeting(f.meetings[id])) {
+		if occurrence.OccurrenceID == occurrenceID && !f.overrides[id][occurrenceID].deleted {
+			return true
+		}
+	}
+	writeError(w, http.StatusNotFound, 3001, fmt.Sprintf("Meeting does not exist: %s.", id))
+	return false
+}
+
+func (f *Fake) setOverrideLocked(id, occurrenceID string, override occurrenceOverride) {
+	if f.overrides[id] == nil {
+		f.overrides[id] = make(map[string]occurrenceOverride)
+	}
+	f.overrides[id][occurrenceID] = override
+}
+
+// meetingForUserLocked looks up a meeting hosted by userID, writing Zoom's
+// 404 response when it does not exist or belongs to someone else
+func (f *Fake) meetingForUserLocked(w http.ResponseWriter, id string, userID string) (map[string]interface{}, bool) {
+	meeting, ok := f.meetings[id]
+	if !ok || meeting["host_id"] != userID {
+		writeError(w, http.StatusNotFound, 3001, fmt.Sprintf("Meeting does not exist: %s.", id))
+		return nil, false
+	}
+	return meeting, true
+}
+
+// sortedMeetingIDs returns the IDs of the meetings hosted by userID (or all
+// meetings when userID is empty) in creation order
+func (f *Fake) sortedMeetingIDs(userID string) []string {
+	var ids []string
+	for id, meeting := range f.meetings {
+		if userID == "" || meeting["host_id"] == userID {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return len(ids[i]) < len(ids[j]) || (len(ids[i]) == len(ids[j]) && ids[i] < ids[j])
+	})
+	return ids
+}
+
+// matchesListType reports whether a stored meeting belongs in a meeting list
+// of the given type
+func matchesListType(stored map[string]interface{}, listType string, now time.Time) bool {
+	meeting := toMeeting(stored)
+	end := meeting.StartTime.Add(time.Duration(meeting.Duration) * time.Minute)
+	for _, occurrence := range meeting.Occurrences {
+		end = occurrence.StartTime.Add(time.Duration(occurrence.Duration) * time.Minute)
+	}
+
+	switch listType {
+	case zoom.ListLive:
+		return meeting.Status == "started"
+	case zoom.ListUpcoming, zoom.ListUpcomingMeetings:
+		return end.After(now)
+	case zoom.ListPreviousMeetings:
+		return !end.After(now)
+	}
+	return true
+}
+
+// mergePatch applies patch to target with JSON merge-patch semantics
+func mergePatch(target map[string]interface{}, patch map[string]interface{}) {
+	for key, value := range patch {
+		if value == nil {
+			delete(target, key)
+			continue
+		}
+		if patchObject, ok := value.(map[string]interface{}); ok {
+			if targetObject, ok := target[key].(map[string]interface{}); ok {
+				mergePatch(targetObject, patchObject)
+				continue
+			}
+		}
+		target[key] = value
+	}
+}
+
+// setOccurrencesLocked recomputes the occurrences of a stored recurring
+// meeting, applying the changes made to single occurrences. Deleted
+// occurrences are left out, as Zoom does.
+func (f *Fake) setOccurrencesLocked(id string, stored map[string]interface{}) {
+	if stored["type"] != float64(zoom.MeetingTypeRecurringFixed) && stored["type"] != zoom.MeetingTypeRecurringFixed {
+		delete(stored, "occurrences")
+		delete(stored, "recurrence")
+		return
+	}
+
+	var occurrences []zoom.Occurrence
+	for _, occurrence := range expandOccurrences(toMeeting(stored)) {
+		override := f.overrides[id][occurrence.OccurrenceID]
+		if override.deleted {
+			continue
+		}
+		if !override.startTime.IsZero() {
+			occurrence.StartTime = override.startTime
+		}
+		if override.duration != 0 {
+			occurrence.Duration = override.duration
+		}
+		occurrences = append(occurrences, occurrence)
+	}
+	stored["occurrences"] = occurrences
+}
+
+func toMeeting(stored map[string]interface{}) *zoom.Meeting {
+	data, _ := json.Marshal(stored)
+	var meeting zoom.Meeting
+	_ = json.Unmarshal(data, &meeting)
+	return &meeting
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, code int, message string) {
+	writeJSON(w, status, map[string]interface{}{"code": code, "message": message})
+}
+
+func randomString(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)[:n]
+}
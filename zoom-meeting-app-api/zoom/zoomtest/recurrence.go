@@ -0,0 +1,102 @@
+package zoomtest
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	"zoom-meeting-app/zoom"
+)
+
+// maxIterations guards the occurrence generator against rules that never match
+const maxIterations = 5000
+
+// expandOccurrences computes the occurrences of a recurring meeting the way
+// Zoom does, in the meeting's time zone so the wall-clock time is kept
+func expandOccurrences(m *zoom.Meeting) []zoom.Occurrence {
+	r := m.Recurrence
+	if m.Type != zoom.MeetingTypeRecurringFixed || r == nil || m.StartTime.IsZero() {
+		return nil
+	}
+
+	loc, err := time.LoadLocation(m.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	start := m.StartTime.In(loc)
+	interval := max(r.RepeatInterval, 1)
+
+	limit := r.EndTimes
+	if limit == 0 && r.EndDateTime == nil {
+		limit = 1 // Zoom's default when no end is given
+	}
+
+	var occurrences []zoom.Occurrence
+	add := func(t time.Time) bool {
+		if r.EndDateTime != nil && t.After(*r.EndDateTime) {
+			return false
+		}
+		if limit > 0 && len(occurrences) >= limit {
+			return false
+		}
+		occurrences = append(occurrences, zoom.Occurrence{
+			OccurrenceID: strconv.FormatInt(t.UnixMilli(), 10),
+			StartTime:    t.UTC(),
+			Duration:     m.Duration,
+			Status:       "available",
+		})
+		return true
+	}
+
+	switch r.Type {
+	case zoom.RecurrenceDaily:
+		for i, t := 0, start; i < maxIterations && add(t); i, t = i+1, t.AddDate(0, 0, interval) {
+		}
+	case zoom.RecurrenceWeekly:
+		days := map[int]bool{}
+		for _, day := range strings.Split(r.WeeklyDays, ",") {
+			if n, err := strconv.Atoi(strings.TrimSpace(day)); err == nil {
+				days[n] = true
+			}
+		}
+		weekStart := start.AddDate(0, 0, -int(start.Weekday()))
+		for i := 0; i < maxIterations; i++ {
+			t := weekStart.AddDate(0, 0, (i/7)*7*interval+i%7)
+			if t.Before(start) || !days[int(t.Weekday())+1] {
+				continue
+			}
+			if !add(t) {
+				break
+			}
+		}
+	case zoom.RecurrenceMonthly:
+		for i := 0; i < maxIterations; i += interval {
+			t, ok := monthlyOccurrence(start, i, r)
+			if !ok || t.Before(start) {
+				continue
+			}
+			if !add(t) {
+				break
+			}
+		}
+	}
+	return occurrences
+}
+
+// monthlyOccurrence returns the occurrence in the month offset months after start
+func monthlyOccurrence(start time.Time, offset int, r *zoom.Recurrence) (time.Time, bool) {
+	first := time.Date(start.Year(), start.Month()+time.Month(offset), 1, start.Hour(), start.Minute(), start.Second(), 0, start.Location())
+
+	if r.MonthlyDay > 0 {
+		t := first.AddDate(0, 0, r.MonthlyDay-1)
+		// Months without that day are skipped
+		return t, t.Month() == first.Month()
+	}
+
+	weekday := time.Weekday(r.MonthlyWeekDay - 1)
+	if r.MonthlyWeek == -1 {
+		last := first.AddDate(0, 1, -1)
+		return last.AddDate(0, 0, -((int(last.Weekday()) - int(weekday) + 7) % 7)), true
+	}
+	t := first.AddDate(0, 0, (int(weekday)-int(first.Weekday())+7)%7+(r.MonthlyWeek-1)*7)
+	return t, t.Month() == first.Month()
+}
@@ -0,0 +1,40 @@
+package zoomtest
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// NewWebhookRequest builds a webhook notification for the given event,
+// signed with secret the way Zoom signs them
+func NewWebhookRequest(target, secret, event string, payload interface{}) (*http.Request, error) {
+	now := time.Now()
+	body, err := json.Marshal(map[string]interface{}{
+		"event":    event,
+		"event_ts": now.UnixMilli(),
+		"payload":  payload,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + timestamp + ":" + string(body)))
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-zm-request-timestamp", timestamp)
+	req.Header.Set("x-zm-signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+	return req, nil
+}